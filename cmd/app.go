@@ -3,7 +3,13 @@ package main
 import (
 	"go-bank-v2/internal/api"
 	"go-bank-v2/internal/infrastructure/postgres"
+	"go-bank-v2/internal/interest"
+	"go-bank-v2/internal/reconciliation"
+	"go-bank-v2/internal/scheduler"
+	"go-bank-v2/internal/types"
 	"log"
+	"os"
+	"time"
 )
 
 type app struct {
@@ -17,6 +23,65 @@ func newApp(config config) *app {
 }
 
 func (a *app) Run() {
+	store := a.openStore()
+
+	if a.config.app.ExchangeRatesFile != "" {
+		if err := loadExchangeRates(store, a.config.app.ExchangeRatesFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	engine, err := interest.NewEngine(a.config.interest, store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	engine.Start()
+
+	payments, err := scheduler.NewScheduler(a.config.scheduler, store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	payments.Start()
+
+	reconciler, err := reconciliation.NewJob(a.config.reconcile, store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	reconciler.Start()
+
+	server, err := api.NewServer(a.config.api, store)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server.Run()
+
+}
+
+// RunInterest accrues and capitalises interest once and exits.
+func (a *app) RunInterest() {
+	engine, err := interest.NewEngine(a.config.interest, a.openStore())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := engine.Run(time.Now()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// RunReconciliation reconciles every account once and exits.
+func (a *app) RunReconciliation() {
+	job, err := reconciliation.NewJob(a.config.reconcile, a.openStore())
+	if err != nil {
+		log.Fatal(err)
+	}
+	run, err := job.Run(time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Reconciliation run %d checked %d accounts, %d out of balance", run.ID, run.Accounts, len(run.Mismatches))
+}
+
+func (a *app) openStore() *postgres.PostgresqlStore {
 	store, err := postgres.NewPostgresStore(a.config.db)
 	if err != nil {
 		log.Fatal(err)
@@ -25,8 +90,20 @@ func (a *app) Run() {
 	if err = store.Migrate(); err != nil {
 		log.Fatal(err)
 	}
+	return store
+}
 
-	server := api.NewServer(a.config.api, store)
-	server.Run()
+func loadExchangeRates(store postgres.Store, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
+	rates, err := types.ParseExchangeRatesCSV(file)
+	if err != nil {
+		return err
+	}
+	log.Printf("Loading %d exchange rates from %s", len(rates), path)
+	return store.SaveExchangeRates(rates)
 }
@@ -5,12 +5,24 @@ import (
 	"github.com/joho/godotenv"
 	. "go-bank-v2/internal/api"
 	. "go-bank-v2/internal/infrastructure/postgres"
+	"go-bank-v2/internal/interest"
+	"go-bank-v2/internal/reconciliation"
+	"go-bank-v2/internal/scheduler"
 	"log"
 )
 
 type config struct {
-	db  DbConfig
-	api RestApiConfig
+	db        DbConfig
+	api       RestApiConfig
+	app       appConfig
+	interest  interest.Config
+	scheduler scheduler.Config
+	reconcile reconciliation.Config
+}
+
+type appConfig struct {
+	// ExchangeRatesFile optionally points to a CSV file of exchange rates loaded on startup.
+	ExchangeRatesFile string `env:"FX_RATES_FILE"`
 }
 
 func loadConfig() config {
@@ -22,6 +34,10 @@ func loadConfig() config {
 	var config config
 	var dbConfig DbConfig
 	var apiConfig RestApiConfig
+	var appConfig appConfig
+	var interestConfig interest.Config
+	var schedulerConfig scheduler.Config
+	var reconciliationConfig reconciliation.Config
 
 	if err := env.Parse(&dbConfig); err != nil {
 		log.Fatalf("Error parsing environment variables: %v", err)
@@ -31,8 +47,28 @@ func loadConfig() config {
 		log.Fatalf("Error parsing environment variables: %v", err)
 	}
 
+	if err := env.Parse(&appConfig); err != nil {
+		log.Fatalf("Error parsing environment variables: %v", err)
+	}
+
+	if err := env.Parse(&interestConfig); err != nil {
+		log.Fatalf("Error parsing environment variables: %v", err)
+	}
+
+	if err := env.Parse(&schedulerConfig); err != nil {
+		log.Fatalf("Error parsing environment variables: %v", err)
+	}
+
+	if err := env.Parse(&reconciliationConfig); err != nil {
+		log.Fatalf("Error parsing environment variables: %v", err)
+	}
+
 	config.db = dbConfig
 	config.api = apiConfig
+	config.app = appConfig
+	config.interest = interestConfig
+	config.scheduler = schedulerConfig
+	config.reconcile = reconciliationConfig
 
 	return config
 }
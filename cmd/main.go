@@ -1,6 +1,23 @@
 package main
 
+import (
+	"log"
+	"os"
+)
+
 func main() {
 	app := newApp(loadConfig())
-	app.Run()
+	if len(os.Args) < 2 {
+		app.Run()
+		return
+	}
+
+	switch os.Args[1] {
+	case "interest":
+		app.RunInterest()
+	case "reconcile":
+		app.RunReconciliation()
+	default:
+		log.Fatalf("Unknown command %q", os.Args[1])
+	}
 }
@@ -17,23 +17,80 @@ import (
 	. "go-bank-v2/internal/types"
 	"log"
 	"net/http"
-	"strconv"
 )
 
 func (s *Server) Run() {
 
 	router := gin.Default()
+	s.router = router
 
 	router.POST("/login", s.handleLogin)
-	router.POST("/users", s.handleCreateUser)
+	router.POST("/users", s.withAdminSignup(s.handleCreateUser))
 	router.GET("/users/:id", withJWTAuth(s.handleGetUser, s.store, false))
 	router.GET("/users/:id/accounts", withJWTAuth(s.handleGetAllUserAccounts, s.store, false))
-	router.DELETE("/users/:id", withJWTAuth(s.handleDeleteUser, s.store, true))
+	router.POST("/users/:id/standing-orders", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateStandingOrder, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.GET("/users/:id/standing-orders", withJWTAuth(s.handleGetStandingOrders, s.store, false))
+	router.DELETE("/users/:id/standing-orders/:orderId", withJWTAuth(withIdempotency(s.withApproval(s.handleCancelStandingOrder, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.GET("/users/:id/standing-orders/:orderId/executions", withJWTAuth(s.handleGetStandingOrderExecutions, s.store, false))
+	router.GET("/users/:id/limits", withJWTAuth(s.handleGetLimits, s.store, false))
+	router.POST("/users/:id/limits", withJWTAuth(withIdempotency(s.withApproval(s.handleSetLimits, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.DELETE("/users/:id/limits", withJWTAuth(withIdempotency(s.withApproval(s.handleResetLimits, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/users/:id/beneficiaries", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateBeneficiary, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.GET("/users/:id/beneficiaries", withJWTAuth(s.handleGetBeneficiaries, s.store, false))
+	router.DELETE("/users/:id/beneficiaries/:beneficiaryId", withJWTAuth(withIdempotency(s.withApproval(s.handleDeleteBeneficiary, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.DELETE("/users/:id", withJWTAuth(withIdempotency(s.withApproval(s.handleDeleteUser, nil), s.store, s.idempotencyTTL), s.store, true))
 	router.GET("/accounts/:accId", withJWTAuth(s.handleGetAccount, s.store, false))
-	router.DELETE("/accounts/:accId", withJWTAuth(s.handleDeleteAccount, s.store, true))
+	router.GET("/accounts/:accId/entries", withJWTAuth(s.handleGetAccountEntries, s.store, false))
+	router.GET("/accounts/:accId/balance", withJWTAuth(s.handleGetBalance, s.store, false))
+	router.GET("/accounts/:accId/statement", withJWTAuth(s.handleGetStatement, s.store, false))
+	router.DELETE("/accounts/:accId", withJWTAuth(withIdempotency(s.withApproval(s.handleDeleteAccount, s.closureSweep), s.store, s.idempotencyTTL), s.store, true))
 	router.GET("/accounts", withJWTAuth(s.handleGetAllAccounts, s.store, true))
-	router.POST("/accounts", withJWTAuth(s.handleCreateAccount, s.store, false))
-	router.PATCH("/accounts/:accId", withJWTAuth(s.handleUpdateAccount, s.store, true))
+	router.POST("/accounts", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateAccount, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.PATCH("/accounts/:accId", withJWTAuth(withIdempotency(s.withApproval(s.handleUpdateAccount, s.balanceAdjustment), s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/accounts/:accId/status", withJWTAuth(withIdempotency(s.withApproval(s.handleUpdateAccountStatus, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/accounts/:accId/interest-rate", withJWTAuth(withIdempotency(s.withApproval(s.handleSetInterestRate, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.GET("/accounts/:accId/interest-accruals", withJWTAuth(s.handleGetInterestAccruals, s.store, false))
+	router.POST("/accounts/:accId/deposits", withJWTAuth(withIdempotency(s.withApproval(s.handleDeposit, s.cashAmount), s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/accounts/:accId/withdrawals", withJWTAuth(withIdempotency(s.withApproval(s.handleWithdrawal, s.cashAmount), s.store, s.idempotencyTTL), s.store, false))
+	router.POST("/accounts/:accId/overdraft", withJWTAuth(withIdempotency(s.withApproval(s.handleSetOverdraftLimit, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.DELETE("/accounts/:accId/overdraft", withJWTAuth(withIdempotency(s.withApproval(s.handleRemoveOverdraftLimit, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.GET("/accounts/:accId/holders", withJWTAuth(s.handleGetAccountHolders, s.store, false))
+	router.POST("/accounts/:accId/holders", withJWTAuth(withIdempotency(s.withApproval(s.handleAddAccountHolder, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.DELETE("/accounts/:accId/holders/:userId", withJWTAuth(withIdempotency(s.withApproval(s.handleRemoveAccountHolder, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.POST("/accounts/:accId/payment-batches", withJWTAuth(withIdempotency(s.withApproval(s.handleCreatePaymentBatch, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.GET("/accounts/:accId/payment-batches", withJWTAuth(s.handleGetPaymentBatches, s.store, false))
+	router.GET("/accounts/:accId/payment-batches/:batchId", withJWTAuth(s.handleGetPaymentBatch, s.store, false))
+	router.POST("/accounts/:accId/payment-batches/:batchId/execute", withJWTAuth(withIdempotency(s.withApproval(s.handleExecutePaymentBatch, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.GET("/accounts/:accId/fees", withJWTAuth(s.handleGetFees, s.store, false))
+	router.POST("/accounts/:accId/loans", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateLoan, s.loanPrincipal), s.store, s.idempotencyTTL), s.store, true))
+	router.GET("/accounts/:accId/loans", withJWTAuth(s.handleGetLoans, s.store, false))
+	router.GET("/accounts/:accId/loans/:loanId", withJWTAuth(s.handleGetLoan, s.store, false))
+	router.GET("/accounts/:accId/loans/:loanId/schedule", withJWTAuth(s.handleGetLoanSchedule, s.store, false))
+	router.POST("/accounts/:accId/term-deposits", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateTermDeposit, s.cashAmount), s.store, s.idempotencyTTL), s.store, false))
+	router.GET("/accounts/:accId/term-deposits", withJWTAuth(s.handleGetTermDeposits, s.store, false))
+	router.GET("/accounts/:accId/term-deposits/:depositId", withJWTAuth(s.handleGetTermDeposit, s.store, false))
+	router.POST("/accounts/:accId/term-deposits/:depositId/withdraw", withJWTAuth(withIdempotency(s.withApproval(s.handleWithdrawTermDeposit, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.POST("/accounts/:accId/term-deposits/:depositId/rollover", withJWTAuth(withIdempotency(s.withApproval(s.handleSetTermDepositRollover, nil), s.store, s.idempotencyTTL), s.store, false))
+	router.POST("/accounts/:accId/holds", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateHold, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.GET("/accounts/:accId/holds", withJWTAuth(s.handleGetHolds, s.store, false))
+	router.GET("/holds/:holdId", withJWTAuth(s.handleGetHold, s.store, true))
+	router.POST("/holds/:holdId/capture", withJWTAuth(withIdempotency(s.withApproval(s.handleCaptureHold, s.captureAmount), s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/holds/:holdId/release", withJWTAuth(withIdempotency(s.withApproval(s.handleReleaseHold, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.GET("/transactions/:id", withJWTAuth(s.handleGetTransaction, s.store, true))
+	router.POST("/transactions/:id/reversal", withJWTAuth(withIdempotency(s.withApproval(s.handleReverseTransaction, s.reversalAmount), s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/transfers", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateTransfer, s.transferAmount), s.store, s.idempotencyTTL), s.store, false))
+	router.GET("/transfers/:transferId", withJWTAuth(s.handleGetTransfer, s.store, false))
+	router.GET("/exchange-rates", withJWTAuth(s.handleGetExchangeRates, s.store, false))
+	router.POST("/exchange-rates", withJWTAuth(withIdempotency(s.withApproval(s.handleCreateExchangeRates, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.GET("/admin/reconciliation/:runId", withJWTAuth(s.handleGetReconciliationRun, s.store, true))
+	router.GET("/admin/fee-schedule", withJWTAuth(s.handleGetFeeSchedule, s.store, true))
+	router.POST("/admin/fee-schedule", withJWTAuth(withIdempotency(s.withApproval(s.handleSetFeeRule, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.DELETE("/admin/fee-schedule/:ruleId", withJWTAuth(withIdempotency(s.withApproval(s.handleDeleteFeeRule, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/fees/:feeId/waive", withJWTAuth(withIdempotency(s.withApproval(s.handleWaiveFee, nil), s.store, s.idempotencyTTL), s.store, true))
+	router.GET("/admin/approvals", withJWTAuth(s.handleGetApprovalRequests, s.store, true))
+	router.GET("/admin/approvals/:approvalId", withJWTAuth(s.handleGetApprovalRequest, s.store, true))
+	router.POST("/admin/approvals/:approvalId/approve", withJWTAuth(withIdempotency(s.handleApproveRequest, s.store, s.idempotencyTTL), s.store, true))
+	router.POST("/admin/approvals/:approvalId/reject", withJWTAuth(withIdempotency(s.handleRejectRequest, s.store, s.idempotencyTTL), s.store, true))
 	router.GET("/swagger-ui/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	fmt.Println("JSON API server running on port:", s.listenAddr)
 	err := router.Run(s.listenAddr)
@@ -47,11 +104,12 @@ type Error struct {
 }
 
 // @Summary Get All User Accounts
-// @Description Fetch all accounts for a user by their ID
+// @Description Fetch all accounts a user holds, in any role, by their ID
 // @Tags account
 // @Accept json
 // @Produce json
-// @Param id path int true "User ID"
+// @Param id path string true "User ID"
+// @Param status query []string false "Only accounts in these statuses" collectionFormat(multi)
 // @Success 200 {array} types.Account
 // @Failure 400 {object} Error "Bad Request"
 // @Failure 401 {object} Error "Unauthorized"
@@ -60,13 +118,19 @@ type Error struct {
 // @Router /users/{id}/accounts [get]
 func (s *Server) handleGetAllUserAccounts(c *gin.Context) {
 	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
+	id, err := parseUserID(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
 		return
 	}
 
-	accounts, err := s.store.GetAccounts(id)
+	statuses, err := statusFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+
+	accounts, err := s.store.GetAccounts(id, statuses...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
 		return
@@ -75,12 +139,25 @@ func (s *Server) handleGetAllUserAccounts(c *gin.Context) {
 
 }
 
+// statusFilter reads the optional, repeatable status query parameter.
+func statusFilter(c *gin.Context) ([]AccountStatus, error) {
+	var statuses []AccountStatus
+	for _, value := range c.QueryArray("status") {
+		status, err := ParseAccountStatus(value)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
 // @Summary Get User by ID
 // @Description Fetch a user by their ID
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param id path int true "User ID"
+// @Param id path string true "User ID"
 // @Success 200 {object} types.UserDto
 // @Failure 400 {object} Error "Bad Request"
 // @Failure 401 {object} Error "Unauthorized"
@@ -94,7 +171,7 @@ func (s *Server) handleGetUser(c *gin.Context) {
 	}
 
 	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
+	id, err := parseUserID(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
 		return
@@ -106,14 +183,19 @@ func (s *Server) handleGetUser(c *gin.Context) {
 		return
 	}
 
-	userDto := UserDto{
+	c.JSON(http.StatusOK, newUserDto(user))
+}
+
+// newUserDto is the user as shown to clients, without the password hash.
+func newUserDto(user *User) UserDto {
+	return UserDto{
 		ID:          user.ID,
 		FirstName:   user.FirstName,
 		LastName:    user.LastName,
 		MemberSince: user.MemberSince,
 		Role:        user.Role,
+		Active:      user.Active,
 	}
-	c.JSON(http.StatusOK, userDto)
 }
 
 // @Security ApiKeyAuth
@@ -123,6 +205,9 @@ func (s *Server) handleGetUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Query id path string true "User ID"
+// @Param currency query string false "ISO 4217 currency of the account, EUR when omitted"
+// @Param type query string false "Account type, current when omitted" Enums(current, savings)
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
 // @Success 200 {object} types.Account
 // @Failure 400 {object} Error "Bad Request"
 // @Router /accounts [post]
@@ -131,18 +216,33 @@ func (s *Server) handleCreateAccount(c *gin.Context) {
 		c.JSON(http.StatusMethodNotAllowed, Error{Error: "Method not allowed"})
 		return
 	}
-	id, err := strconv.Atoi(c.Query("ownerId"))
+	id, err := parseUserID(c.Query("ownerId"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, Error{Error: "Couldn't create an account!"})
 		fmt.Println(err)
 		return
 	}
 
-	acc := NewAccount(id)
+	currency := DefaultCurrency
+	if c.Query("currency") != "" {
+		currency, err = ParseCurrency(c.Query("currency"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+	}
+
+	acc := NewAccount(id, currency)
+	if c.Query("type") != "" {
+		acc.Type, err = ParseAccountType(c.Query("type"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+	}
 	err = s.store.CreateAccount(acc)
 	if err != nil {
-		c.JSON(http.StatusMethodNotAllowed, Error{Error: "Couldn't create account!"})
-		log.Fatal(err)
+		respondWithError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, acc)
@@ -163,7 +263,7 @@ func (s *Server) handleLogin(c *gin.Context) {
 		c.JSON(http.StatusMethodNotAllowed, Error{Error: "Method not allowed"})
 		return
 	}
-	id, err := strconv.Atoi(c.Query("id"))
+	id, err := parseUserID(c.Query("id"))
 	password := c.Query("password")
 
 	if err != nil || password == "" {
@@ -180,7 +280,7 @@ func (s *Server) handleLogin(c *gin.Context) {
 		return
 	}
 
-	if !user.ValidPassword(req.Password) {
+	if user == nil || !user.Active || !user.ValidPassword(req.Password) {
 		c.JSON(http.StatusUnauthorized, Error{Error: "Unauthorized"})
 		return
 	}
@@ -201,15 +301,32 @@ func (s *Server) handleLogin(c *gin.Context) {
 
 }
 
+// withAdminSignup leaves signing up open to anyone but treats creating an admin like any other
+// admin operation: it takes an admin caller and, where configured, the approval of a second admin.
+func (s *Server) withAdminSignup(handlerFunc gin.HandlerFunc) gin.HandlerFunc {
+	asAdmin := withJWTAuth(withIdempotency(s.withApproval(handlerFunc, nil), s.store, s.idempotencyTTL), s.store, true)
+	return func(c *gin.Context) {
+		if c.Query("role") == string(AdminRole) {
+			asAdmin(c)
+			return
+		}
+		handlerFunc(c)
+	}
+}
+
+// @Security ApiKeyAuth
 // @Summary Create user
-// @Description Creates a new user
+// @Description Creates a new user. Anyone may sign up; creating an admin is admin-only
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id query string true "User ID"
 // @Param password query string true "User password"
-// @Success 200 {object} types.User
+// @Param role query string true "Role of the new user" Enums(user, admin)
+// @Success 200 {object} types.UserDto
+// @Success 202 {object} types.ApprovalRequest "Held back until a second admin approves it"
 // @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
 // @Router /users [post]
 func (s *Server) handleCreateUser(c *gin.Context) {
 	if c.Request.Method != "POST" {
@@ -243,7 +360,7 @@ func (s *Server) handleCreateUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, newUserDto(user))
 }
 
 // @Security ApiKeyAuth
@@ -258,7 +375,7 @@ func (s *Server) handleCreateUser(c *gin.Context) {
 // @Router /accounts/{id} [get]
 func (s *Server) handleGetAccount(c *gin.Context) {
 	accNumStr := c.Param("accId")
-	accNum, err := strconv.Atoi(accNumStr)
+	accNum, err := parseAccountNumber(accNumStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
 		return
@@ -271,15 +388,51 @@ func (s *Server) handleGetAccount(c *gin.Context) {
 	c.JSON(http.StatusOK, account)
 }
 
-// @Summary Update Account
-// @Description Update an account's balance by account number
+// @Security ApiKeyAuth
+// @Summary Get Account Ledger Entries
+// @Description Fetch every ledger entry booked on an account, oldest first
 // @Tags account
 // @Accept json
 // @Produce json
-// @Param id path int true "Account ID"
-// @Param newBalance query number true "New Balance"
+// @Param id path string true "Account ID"
+// @Success 200 {array} types.LedgerEntry
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/entries [get]
+func (s *Server) handleGetAccountEntries(c *gin.Context) {
+	accNumStr := c.Param("accId")
+	accNum, err := parseAccountNumber(accNumStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+
+	entries, err := s.store.GetLedgerEntries(account.AccountNumber)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// @Summary Adjust Account Balance
+// @Description Admin-only correction that sets an account's balance. The difference is booked as an audited adjustment
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param newBalance query string true "New balance as a decimal string, e.g. 125.50"
+// @Param reason query string true "Why the balance is adjusted"
 // @Security ApiKeyAuth
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
 // @Success 200 {object} types.Account
+// @Success 202 {object} types.ApprovalRequest "Held back until a second admin approves it"
 // @Failure 400 {object} Error "Bad Request"
 // @Failure 401 {object} Error "Unauthorized"
 // @Failure 403 {object} Error "Forbidden"
@@ -288,7 +441,7 @@ func (s *Server) handleGetAccount(c *gin.Context) {
 // @Router /accounts/{id} [patch]
 func (s *Server) handleUpdateAccount(c *gin.Context) {
 	accNumStr := c.Param("accId")
-	accNum, err := strconv.Atoi(accNumStr)
+	accNum, err := parseAccountNumber(accNumStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
 		return
@@ -300,37 +453,52 @@ func (s *Server) handleUpdateAccount(c *gin.Context) {
 		return
 	}
 
-	newBalance, err := strconv.ParseFloat(c.Query("newBalance"), 32)
+	newBalance, err := ParseMoney(c.Query("newBalance"), account.Balance.Currency)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Error{Error: "Invalid balance update!"})
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid balance update!"})
+		return
+	}
+	reason := c.Query("reason")
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, Error{Error: "A reason is required for balance adjustments"})
 		return
 	}
-	err = s.store.UpdateAccountBalance(account, newBalance)
+	_, err = s.store.AdjustAccountBalance(account, newBalance, reason, callerFromContext(c).ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		respondWithError(c, err)
+		return
+	}
+
+	account, err = s.store.GetAccountByNumber(accNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
 		return
 	}
-	account.Balance = newBalance
 	c.JSON(http.StatusOK, account)
 }
 
-// @Summary Delete Account
-// @Description Delete an account by account number
+// @Summary Close Account
+// @Description Close an account. Accounts are never deleted; a remaining balance must be swept to another account and
+// @Description active holds captured or released first
 // @Tags account
 // @Accept json
 // @Produce json
-// @Param id path int true "Account ID"
+// @Param id path string true "Account ID"
+// @Param sweepTo query string false "Account receiving the remaining balance"
+// @Param reason query string false "Why the account is closed"
 // @Security ApiKeyAuth
-// @Success 200 {object} string
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Account
 // @Failure 400 {object} Error "Bad Request"
 // @Failure 401 {object} Error "Unauthorized"
 // @Failure 403 {object} Error "Forbidden"
 // @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Account can't be closed in its current state"
 // @Failure 500 {object} Error "Internal Server Error"
 // @Router /accounts/{id} [delete]
 func (s *Server) handleDeleteAccount(c *gin.Context) {
 	accNumStr := c.Param("accId")
-	accNum, err := strconv.Atoi(accNumStr)
+	accNum, err := parseAccountNumber(accNumStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
 		return
@@ -342,72 +510,121 @@ func (s *Server) handleDeleteAccount(c *gin.Context) {
 		return
 	}
 
-	err = s.store.DeleteAccount(accNum)
+	sweepTo := c.Query("sweepTo")
+	if sweepTo != "" {
+		if sweepTo, err = parseAccountNumber(sweepTo); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid sweep account"})
+			return
+		}
+	}
+
+	_, err = s.store.CloseAccount(accNum, sweepTo, c.Query("reason"), callerFromContext(c).ID)
 	if err != nil {
-		c.JSON(http.StatusAccepted, Error{Error: "Scheduled for deletion!"})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, "Account successfully deleted!")
+	account, err = s.store.GetAccountByNumber(accNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, account)
 }
 
 // @Security ApiKeyAuth
-// @Summary Delete User by ID
-// @Description Deletes a user by their ID, and deletes all the users accounts
-// @Tags users
+// @Summary Change Account Status
+// @Description Admin-only. Freeze, unfreeze, mark dormant or reactivate an account. Use DELETE to close it
+// @Tags account
 // @Accept json
 // @Produce json
 // @Param id path string true "Account ID"
+// @Param status query string true "New status" Enums(active, frozen, dormant)
+// @Param reason query string false "Why the status changes"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
 // @Success 200 {object} types.Account
 // @Failure 400 {object} Error "Bad Request"
 // @Failure 403 {object} Error "Forbidden"
-// @Router /users/{id} [delete]
-func (s *Server) handleDeleteUser(c *gin.Context) {
-	if c.Request.Method != "DELETE" {
-		c.JSON(http.StatusMethodNotAllowed, Error{Error: "Method not allowed"})
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Transition not allowed"
+// @Router /accounts/{id}/status [post]
+func (s *Server) handleUpdateAccountStatus(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	status, err := ParseAccountStatus(c.Query("status"))
+	if err != nil || status == ClosedAccount {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid status"})
 		return
 	}
 
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+	if err := s.store.UpdateAccountStatus(accNum, status, c.Query("reason"), callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	accounts, err := s.store.GetAccounts(id)
+	account, err := s.store.GetAccountByNumber(accNum)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Error{Error: "Can't get accounts"})
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, account)
+}
 
-	for _, acc := range accounts {
-		err = s.store.DeleteAccount(acc.AccountNumber)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, Error{Error: "Can't delete accounts"})
-			return
-		}
+// @Security ApiKeyAuth
+// @Summary Deactivate User by ID
+// @Description Closes all of the user's accounts and deactivates the user. History is kept; accounts with money left block deactivation
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} string
+// @Success 202 {object} types.ApprovalRequest "Held back until a second admin approves it"
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 409 {object} Error "User has accounts that can't be closed"
+// @Router /users/{id} [delete]
+func (s *Server) handleDeleteUser(c *gin.Context) {
+	if c.Request.Method != "DELETE" {
+		c.JSON(http.StatusMethodNotAllowed, Error{Error: "Method not allowed"})
+		return
 	}
 
-	err = s.store.DeleteUser(id)
+	idStr := c.Param("id")
+	id, err := parseUserID(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
 		return
 	}
-	c.JSON(http.StatusOK, "User deleted!")
+
+	err = s.store.DeactivateUser(id, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, "User deactivated!")
 }
 
 // @Security ApiKeyAuth
 // @Summary Get All Accounts
-// @Description Fetch an account by account number
+// @Description Fetch all accounts, optionally filtered by status
 // @Tags account
 // @Accept json
 // @Produce json
-// @Success 200 {object} types.Account
+// @Param status query []string false "Only accounts in these statuses" collectionFormat(multi)
+// @Success 200 {array} types.Account
 // @Failure 400 {object} Error "Bad Request"
 // @Router /accounts [get]
 func (s *Server) handleGetAllAccounts(c *gin.Context) {
-	accounts, err := s.store.GetAllAccounts()
+	statuses, err := statusFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	accounts, err := s.store.GetAllAccounts(statuses...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
 		return
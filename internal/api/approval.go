@@ -0,0 +1,397 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// approvedKey marks a request that is executed after its approval. It lives in the request context,
+// where clients can't set it.
+type approvedKey struct{}
+
+// approvalAmount returns the amount an operation moves, or nil if it moves none or the request is
+// invalid, which the handler reports itself.
+type approvalAmount func(c *gin.Context) *Money
+
+// withApproval holds back admin operations that need a second admin: those on a configured route
+// and those moving more than the approval threshold. Instead of running the handler it stores the
+// request and answers 202 Accepted with the approval request; once another admin approves it, the
+// request is executed on behalf of its maker. Other users' requests pass through unchanged.
+func (s *Server) withApproval(handlerFunc gin.HandlerFunc, amount approvalAmount) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller := callerFromContext(c)
+		if caller == nil || caller.Role != AdminRole || c.Request.Context().Value(approvedKey{}) != nil {
+			handlerFunc(c)
+			return
+		}
+
+		var moved *Money
+		if amount != nil {
+			moved = amount(c)
+		}
+		needed, err := s.needsApproval(c, moved)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+			return
+		}
+		if !needed {
+			handlerFunc(c)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Couldn't read request body"})
+			return
+		}
+		request := NewApprovalRequest(c.Request.Method, c.FullPath(), c.Request.URL.Path, c.Request.URL.RawQuery, body, c.GetHeader("Content-Type"), moved, caller.ID, s.approvalTTL)
+		if err := s.store.CreateApprovalRequest(request); err != nil {
+			c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, request)
+	}
+}
+
+// needsApproval tells whether an admin operation needs a second admin. Amounts in other currencies
+// are compared to the threshold at the current rate; without one the operation counts as large.
+func (s *Server) needsApproval(c *gin.Context, amount *Money) (bool, error) {
+	if s.approvalRoutes[c.Request.Method+" "+c.FullPath()] {
+		return true, nil
+	}
+	if amount == nil || s.approvalThreshold == nil {
+		return false, nil
+	}
+	threshold := *s.approvalThreshold
+	if amount.Currency != threshold.Currency {
+		rate, err := s.store.GetExchangeRate(amount.Currency, threshold.Currency, time.Now())
+		if errors.Is(err, ErrNoExchangeRate) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		converted, err := rate.Convert(*amount)
+		if err != nil {
+			return false, err
+		}
+		amount = &converted
+	}
+	cmp, err := amount.Cmp(threshold)
+	return cmp > 0, err
+}
+
+// balanceAdjustment is how far a balance adjustment moves the balance of the account of the route.
+func (s *Server) balanceAdjustment(c *gin.Context) *Money {
+	account := s.routeAccount(c)
+	if account == nil {
+		return nil
+	}
+	newBalance, err := ParseMoney(c.Query("newBalance"), account.Balance.Currency)
+	if err != nil {
+		return nil
+	}
+	difference, err := newBalance.Sub(account.Balance)
+	if err != nil {
+		return nil
+	}
+	difference = difference.Abs()
+	return &difference
+}
+
+// cashAmount is the amount of a deposit, withdrawal or term deposit on the account of the route.
+func (s *Server) cashAmount(c *gin.Context) *Money {
+	account := s.routeAccount(c)
+	if account == nil {
+		return nil
+	}
+	amount, err := ParseMoney(c.Query("amount"), account.Balance.Currency)
+	if err != nil {
+		return nil
+	}
+	return &amount
+}
+
+// transferAmount is the amount of a transfer in the currency of its source account.
+func (s *Server) transferAmount(c *gin.Context) *Money {
+	from, err := parseAccountNumber(c.Query("from"))
+	if err != nil {
+		return nil
+	}
+	source, err := s.store.GetAccountByNumber(from)
+	if err != nil || source == nil {
+		return nil
+	}
+	amount, err := ParseMoney(c.Query("amount"), source.Balance.Currency)
+	if err != nil {
+		return nil
+	}
+	return &amount
+}
+
+// reversalAmount is the amount of a reversal; without one, the whole transaction counts.
+func (s *Server) reversalAmount(c *gin.Context) *Money {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	original, err := s.store.GetTransaction(id)
+	if err != nil || original == nil || len(original.Entries) == 0 {
+		return nil
+	}
+	amount := original.Entries[0].Amount.Abs()
+	if c.Query("amount") != "" {
+		if amount, err = ParseMoney(c.Query("amount"), amount.Currency); err != nil {
+			return nil
+		}
+	}
+	return &amount
+}
+
+// loanPrincipal is the principal of a loan paid into the account of the route.
+func (s *Server) loanPrincipal(c *gin.Context) *Money {
+	account := s.routeAccount(c)
+	if account == nil {
+		return nil
+	}
+	principal, err := ParseMoney(c.Query("principal"), account.Balance.Currency)
+	if err != nil {
+		return nil
+	}
+	return &principal
+}
+
+// captureAmount is the amount of a hold capture; without one, everything still held counts.
+func (s *Server) captureAmount(c *gin.Context) *Money {
+	id, err := strconv.ParseInt(c.Param("holdId"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	hold, err := s.store.GetHold(id)
+	if err != nil || hold == nil {
+		return nil
+	}
+	amount := hold.Remaining()
+	if c.Query("amount") != "" {
+		if amount, err = ParseMoney(c.Query("amount"), hold.Amount.Currency); err != nil {
+			return nil
+		}
+	}
+	return &amount
+}
+
+// closureSweep is the balance swept to another account when the account of the route is closed.
+func (s *Server) closureSweep(c *gin.Context) *Money {
+	if c.Query("sweepTo") == "" {
+		return nil
+	}
+	account := s.routeAccount(c)
+	if account == nil || !account.Balance.IsPositive() {
+		return nil
+	}
+	return &account.Balance
+}
+
+func (s *Server) routeAccount(c *gin.Context) *Account {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		return nil
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil {
+		return nil
+	}
+	return account
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Approval Requests
+// @Description Admin-only. List the operations held back for a second admin, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query []string false "Only requests in these statuses" collectionFormat(multi)
+// @Success 200 {array} types.ApprovalRequest
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/approvals [get]
+func (s *Server) handleGetApprovalRequests(c *gin.Context) {
+	var statuses []ApprovalStatus
+	for _, value := range c.QueryArray("status") {
+		status, err := ParseApprovalStatus(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+		statuses = append(statuses, status)
+	}
+	requests, err := s.store.GetApprovalRequests(statuses...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Approval Request
+// @Description Admin-only. Fetch an operation held back for a second admin, with its response once executed
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param approvalId path int true "Approval request ID"
+// @Success 200 {object} types.ApprovalRequest
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/approvals/{approvalId} [get]
+func (s *Server) handleGetApprovalRequest(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("approvalId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid approval request ID"})
+		return
+	}
+	request, err := s.store.GetApprovalRequest(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if request == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "Approval request not found"})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// @Security ApiKeyAuth
+// @Summary Approve Request
+// @Description Admin-only. Approve a pending operation of another admin and execute it on their behalf. The response
+// @Description of the operation is returned with the request; an operation that fails stays approved and is not retried
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param approvalId path int true "Approval request ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.ApprovalRequest
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "The request was made by the caller"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The request is no longer pending"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/approvals/{approvalId}/approve [post]
+func (s *Server) handleApproveRequest(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("approvalId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid approval request ID"})
+		return
+	}
+	request, err := s.store.ApproveRequest(id, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	request.Complete(s.executeApprovalRequest(c.Request.Context(), request))
+	if err := s.store.CompleteApprovalRequest(request); err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// @Security ApiKeyAuth
+// @Summary Reject Request
+// @Description Admin-only. Reject a pending operation of another admin; it is never executed
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param approvalId path int true "Approval request ID"
+// @Param reason query string true "Why the operation is rejected"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.ApprovalRequest
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "The request was made by the caller"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The request is no longer pending"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/approvals/{approvalId}/reject [post]
+func (s *Server) handleRejectRequest(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("approvalId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid approval request ID"})
+		return
+	}
+	reason := c.Query("reason")
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, Error{Error: "A reason is required"})
+		return
+	}
+	request, err := s.store.RejectRequest(id, callerFromContext(c).ID, reason)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// executeApprovalRequest sends an approved request through the router again, authenticated as its
+// maker and with the body and media type it was made with, and returns the response. The maker
+// must still be an active admin for it to succeed.
+func (s *Server) executeApprovalRequest(ctx context.Context, request *ApprovalRequest) (int, []byte) {
+	token, err := createJWT(&User{ID: request.MakerID})
+	if err != nil {
+		return http.StatusInternalServerError, nil
+	}
+	target := request.Path
+	if request.Query != "" {
+		target += "?" + request.Query
+	}
+	ctx = context.WithValue(ctx, approvedKey{}, request.ID)
+	req, err := http.NewRequestWithContext(ctx, request.Method, target, bytes.NewBufferString(request.Body))
+	if err != nil {
+		return http.StatusInternalServerError, nil
+	}
+	req.Header.Set("x-jwt-token", token)
+	// Requests held back before their media type was kept were all JSON.
+	if request.ContentType != "" {
+		req.Header.Set("Content-Type", request.ContentType)
+	} else if request.Body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	response := &bufferedResponse{header: http.Header{}}
+	s.router.ServeHTTP(response, req)
+	return response.status, response.body.Bytes()
+}
+
+// bufferedResponse collects the response to a request the server sends to itself.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *bufferedResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferedResponse) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *bufferedResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNeedsApprovalAboveThreshold(t *testing.T) {
+	threshold := NewMoney(1000000, "EUR")
+	s := &Server{approvalThreshold: &threshold}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/transfers", nil)
+
+	for _, tt := range []struct {
+		amount int64
+		want   bool
+	}{
+		{amount: 999999, want: false},
+		{amount: 1000000, want: false},
+		{amount: 1000001, want: true},
+	} {
+		amount := NewMoney(tt.amount, "EUR")
+		needed, err := s.needsApproval(c, &amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if needed != tt.want {
+			t.Errorf("%s needs approval: %t, want %t", amount.Format(), needed, tt.want)
+		}
+	}
+}
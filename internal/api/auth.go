@@ -8,9 +8,15 @@ import (
 	. "go-bank-v2/internal/types"
 	"net/http"
 	"os"
-	"strconv"
 )
 
+// callerKey is the gin context key under which withJWTAuth stores the authenticated user.
+const callerKey = "caller"
+
+// holderKey is the gin context key under which withJWTAuth stores how the caller holds the
+// account of the route. It is not set for admins.
+const holderKey = "holder"
+
 func withJWTAuth(handlerFunc gin.HandlerFunc, s Store, adminOnly bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		fmt.Println("calling JWT auth middleware")
@@ -26,36 +32,85 @@ func withJWTAuth(handlerFunc gin.HandlerFunc, s Store, adminOnly bool) gin.Handl
 			return
 		}
 		claims := token.Claims.(jwt.MapClaims)
-		if checkIfAdminToken(claims["ownerId"].(float64), s) {
-			handlerFunc(c)
+		ownerID, ok := claims["ownerId"].(string)
+		if !ok {
+			permissionDenied(c)
 			return
 		}
-
-		if adminOnly && !checkIfAdminToken(claims["ownerId"].(float64), s) {
+		caller, err := s.GetUserByID(ownerID)
+		if err != nil || caller == nil || !caller.Active {
 			permissionDenied(c)
 			return
 		}
+		c.Set(callerKey, caller)
 
-		userID, err := getIDFromContext(c)
-		if err != nil {
-			permissionDenied(c)
+		if caller.Role == AdminRole {
+			handlerFunc(c)
 			return
 		}
-		user, err := s.GetUserByID(userID)
-		if err != nil || user == nil {
+
+		if adminOnly {
 			permissionDenied(c)
 			return
 		}
 
-		if user.ID != int(claims["ownerId"].(float64)) {
-			permissionDenied(c)
+		// Routes addressing an account are only open to its holders. Every holder may look at the
+		// account; anything else needs a holder who may move money.
+		if c.Param("accId") != "" {
+			holder := accountHolder(c, caller, s)
+			if holder == nil || (c.Request.Method != http.MethodGet && !holder.CanMoveMoney()) {
+				permissionDenied(c)
+				return
+			}
+			c.Set(holderKey, holder)
+			handlerFunc(c)
 			return
 		}
 
+		// Routes addressing a user are only open to that user. Routes without either
+		// identifier check ownership of whatever they touch in the handler itself.
+		if c.Param("id") != "" || c.Query("ownerId") != "" {
+			userID, err := getIDFromContext(c)
+			if err != nil || userID != caller.ID {
+				permissionDenied(c)
+				return
+			}
+		}
+
 		handlerFunc(c)
 	}
 }
 
+func accountHolder(c *gin.Context, caller *User, s Store) *AccountHolder {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		return nil
+	}
+	holder, err := s.GetAccountHolder(accNum, caller.ID)
+	if err != nil {
+		return nil
+	}
+	return holder
+}
+
+// holderFromContext returns how the caller holds the account of the route, or nil for admins.
+func holderFromContext(c *gin.Context) *AccountHolder {
+	holder, ok := c.Get(holderKey)
+	if !ok {
+		return nil
+	}
+	return holder.(*AccountHolder)
+}
+
+// callerFromContext returns the user authenticated by withJWTAuth.
+func callerFromContext(c *gin.Context) *User {
+	caller, ok := c.Get(callerKey)
+	if !ok {
+		return nil
+	}
+	return caller.(*User)
+}
+
 func permissionDenied(c *gin.Context) {
 	c.JSON(http.StatusForbidden, Error{Error: "Permission denied"})
 	c.Abort()
@@ -84,27 +139,31 @@ func createJWT(user *User) (string, error) {
 	return token.SignedString([]byte(secret))
 }
 
+// parseAccountNumber validates an account number taken from the request.
+func parseAccountNumber(s string) (string, error) {
+	if err := ValidateAccountNumber(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// parseUserID validates a user ID taken from the request.
+func parseUserID(s string) (string, error) {
+	if err := ValidateUserID(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
 // This function extracts the user ID from the Gin context.
-func getIDFromContext(c *gin.Context) (int, error) {
+func getIDFromContext(c *gin.Context) (string, error) {
 	idStr := c.Param("id")
 	if idStr == "" {
 		idStr = c.Query("ownerId")
 	}
-	id, err := strconv.Atoi(idStr)
+	id, err := parseUserID(idStr)
 	if err != nil {
 		return id, fmt.Errorf("invalid ID")
 	}
 	return id, nil
 }
-
-func checkIfAdminToken(id float64, s Store) bool {
-
-	user, err := s.GetUserByID(int(id))
-	if err != nil {
-		return false
-	}
-	if user.Role == AdminRole {
-		return true
-	}
-	return false
-}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Get Historical Balance
+// @Description The balance of an account at a point in time, counting every movement booked before it. A plain date
+// @Description gives the balance at the end of that day (UTC); without a time the current balance is returned
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param at query string false "Date or RFC 3339 timestamp"
+// @Success 200 {object} types.HistoricalBalance
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/balance [get]
+func (s *Server) handleGetBalance(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	at := time.Now()
+	if atStr := c.Query("at"); atStr != "" {
+		if at, err = ParseDateOrTime(atStr); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid at"})
+			return
+		}
+		if !strings.Contains(atStr, "T") {
+			at = at.AddDate(0, 0, 1)
+		}
+	}
+
+	balance, err := s.store.GetBalanceAt(accNum, at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if balance == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+	c.JSON(http.StatusOK, HistoricalBalance{AccountNumber: accNum, Balance: *balance, At: at})
+}
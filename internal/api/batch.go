@@ -0,0 +1,187 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"go-bank-v2/internal/batch"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+)
+
+// @Security ApiKeyAuth
+// @Summary Upload Payment Batch
+// @Description Upload a file of payments from the account, either CSV (account,amount[,reference[,name]] per line,
+// @Description header optional) or ISO 20022 pain.001 XML. Every line is checked against the accounts it pays into and
+// @Description the funds of the account, and the batch is stored as a preview with the outcome of each line. Large
+// @Description payments to beneficiaries in their cooling-off period are invalid. Nothing is paid until the batch is
+// @Description executed
+// @Tags account
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param file formData file true "Payment file"
+// @Param format query string false "Format of the file, taken from the file name when omitted" Enums(csv, pain.001)
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.PaymentBatch
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The account is not active"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/payment-batches [post]
+func (s *Server) handleCreatePaymentBatch(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Missing payment file"})
+		return
+	}
+	format, err := batch.ParseFormat(c.Query("format"), header.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Couldn't read uploaded file"})
+		return
+	}
+	defer file.Close()
+	instructions, err := batch.Read(file, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if account == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "Account not found"})
+		return
+	}
+	paymentBatch := NewPaymentBatch(account, format, instructions, callerFromContext(c).ID)
+	for _, line := range paymentBatch.Lines {
+		if line.Status != ValidLine {
+			continue
+		}
+		if err := s.checkCoolingOff(paymentBatch.CreatedBy, line.ToAccount, line.Amount); err != nil {
+			if !errors.Is(err, ErrBeneficiaryCoolingOff) {
+				c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+				return
+			}
+			line.Invalidate(err)
+		}
+	}
+	if err := s.store.CreatePaymentBatch(paymentBatch); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, paymentBatch)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Payment Batches
+// @Description List the payment batches uploaded for an account, newest first, without their lines
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {array} types.PaymentBatch
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/payment-batches [get]
+func (s *Server) handleGetPaymentBatches(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	batches, err := s.store.GetPaymentBatches(accNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, batches)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Payment Batch
+// @Description Fetch a payment batch with the status of every line
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param batchId path int true "Batch ID"
+// @Success 200 {object} types.PaymentBatch
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/payment-batches/{batchId} [get]
+func (s *Server) handleGetPaymentBatch(c *gin.Context) {
+	paymentBatch, ok := s.paymentBatchFromPath(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, paymentBatch)
+}
+
+// @Security ApiKeyAuth
+// @Summary Execute Payment Batch
+// @Description Confirm a previewed batch and make its payments in file order. Lines that were invalid in the preview
+// @Description are skipped; lines the bank rejects now (e.g. because the funds ran out) are marked failed without
+// @Description stopping the others. A batch is executed only once
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param batchId path int true "Batch ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.PaymentBatch
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The batch has already been executed"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/payment-batches/{batchId}/execute [post]
+func (s *Server) handleExecutePaymentBatch(c *gin.Context) {
+	paymentBatch, ok := s.paymentBatchFromPath(c)
+	if !ok {
+		return
+	}
+	executed, err := s.store.ExecutePaymentBatch(paymentBatch.ID, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, executed)
+}
+
+// paymentBatchFromPath returns the batch named by the batchId parameter if it belongs to the account
+// of the route. It writes the error response itself.
+func (s *Server) paymentBatchFromPath(c *gin.Context) (*PaymentBatch, bool) {
+	id, err := strconv.ParseInt(c.Param("batchId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid batch ID"})
+		return nil, false
+	}
+	paymentBatch, err := s.store.GetPaymentBatch(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return nil, false
+	}
+	if paymentBatch == nil || paymentBatch.AccountNumber != c.Param("accId") {
+		c.JSON(http.StatusNotFound, Error{Error: "Payment batch not found"})
+		return nil, false
+	}
+	return paymentBatch, true
+}
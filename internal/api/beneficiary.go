@@ -0,0 +1,166 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Create Beneficiary
+// @Description Save a payee to transfer to by beneficiary ID later. Account numbers of this bank must belong to an open
+// @Description account; other valid account numbers are saved as external beneficiaries. Large transfers to a new
+// @Description beneficiary are only possible after a cooling-off period
+// @Tags beneficiaries
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param nickname query string true "Name to show for the beneficiary"
+// @Param accountNumber query string true "Account number of the beneficiary"
+// @Param reference query string false "Reference used for transfers that don't give one"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Beneficiary
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Beneficiary already saved"
+// @Router /users/{id}/beneficiaries [post]
+func (s *Server) handleCreateBeneficiary(c *gin.Context) {
+	userID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return
+	}
+	accountNumber, err := parseAccountNumber(c.Query("accountNumber"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid account number"})
+		return
+	}
+	b, err := NewBeneficiary(userID, c.Query("nickname"), accountNumber, c.Query("reference"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.CreateBeneficiary(b); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Beneficiaries
+// @Description List the beneficiaries a user saved, by nickname
+// @Tags beneficiaries
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} types.Beneficiary
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /users/{id}/beneficiaries [get]
+func (s *Server) handleGetBeneficiaries(c *gin.Context) {
+	userID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return
+	}
+	beneficiaries, err := s.store.GetBeneficiaries(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, beneficiaries)
+}
+
+// @Security ApiKeyAuth
+// @Summary Delete Beneficiary
+// @Description Remove a beneficiary from the user's list. Saving it again starts a new cooling-off period
+// @Tags beneficiaries
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param beneficiaryId path int true "Beneficiary ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 204
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Router /users/{id}/beneficiaries/{beneficiaryId} [delete]
+func (s *Server) handleDeleteBeneficiary(c *gin.Context) {
+	userID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("beneficiaryId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid beneficiary ID"})
+		return
+	}
+	b, err := s.store.GetBeneficiary(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if b == nil || b.UserID != userID {
+		c.JSON(http.StatusNotFound, Error{Error: "No such beneficiary"})
+		return
+	}
+	if err := s.store.DeleteBeneficiary(id, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// beneficiaryFromQuery loads the beneficiary named by the beneficiaryId parameter, making sure it
+// belongs to the caller. It writes the error response itself.
+func (s *Server) beneficiaryFromQuery(c *gin.Context, caller *User) (*Beneficiary, bool) {
+	id, err := strconv.ParseInt(c.Query("beneficiaryId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid beneficiary ID"})
+		return nil, false
+	}
+	b, err := s.store.GetBeneficiary(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return nil, false
+	}
+	if b == nil || b.UserID != caller.ID {
+		c.JSON(http.StatusNotFound, Error{Error: "No such beneficiary"})
+		return nil, false
+	}
+	return b, true
+}
+
+// checkCoolingOff rejects a large payment to an account the user saved as a beneficiary only recently,
+// however the destination is given and even if the beneficiary has been removed again. Amounts in
+// other currencies are compared at the current rate; without one the payment counts as large.
+func (s *Server) checkCoolingOff(userID string, to string, amount Money) error {
+	if s.coolingOff <= 0 {
+		return nil
+	}
+	b, err := s.store.LastSavedBeneficiary(userID, to)
+	if err != nil || b == nil {
+		return err
+	}
+	now := time.Now()
+	if amount.Currency != s.coolingOffThreshold.Currency {
+		rate, err := s.store.GetExchangeRate(amount.Currency, s.coolingOffThreshold.Currency, now)
+		if errors.Is(err, ErrNoExchangeRate) {
+			return b.CheckCoolingOff(now, s.coolingOff, nil, s.coolingOffThreshold)
+		}
+		if err != nil {
+			return err
+		}
+		if amount, err = rate.Convert(amount); err != nil {
+			return err
+		}
+	}
+	return b.CheckCoolingOff(now, s.coolingOff, &amount, s.coolingOffThreshold)
+}
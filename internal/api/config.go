@@ -1,6 +1,26 @@
 package api
 
+import "time"
+
 type RestApiConfig struct {
-	BasePath string `env:"API_BASE_PATH"`
-	Port     string `env:"API_PORT"`
+	BasePath       string        `env:"API_BASE_PATH"`
+	Port           string        `env:"API_PORT"`
+	IdempotencyTTL time.Duration `env:"API_IDEMPOTENCY_TTL" envDefault:"24h"`
+	// HoldTTL is how long an authorization hold lasts when the request doesn't say.
+	HoldTTL time.Duration `env:"API_HOLD_TTL" envDefault:"168h"`
+	// TermDepositRate and TermDepositPenalty are the annual rate and early-withdrawal penalty of the
+	// term deposits customers open themselves; admins may agree on others.
+	TermDepositRate    string `env:"API_TERM_DEPOSIT_RATE" envDefault:"0.02"`
+	TermDepositPenalty string `env:"API_TERM_DEPOSIT_PENALTY" envDefault:"0"`
+	// Transfers above CoolingOffAmount (in CoolingOffCurrency) to a beneficiary saved less than
+	// CoolingOff ago are rejected. Zero disables the cooling-off period.
+	CoolingOff         time.Duration `env:"API_BENEFICIARY_COOLING_OFF" envDefault:"24h"`
+	CoolingOffAmount   string        `env:"API_BENEFICIARY_COOLING_OFF_AMOUNT" envDefault:"1000"`
+	CoolingOffCurrency string        `env:"API_BENEFICIARY_COOLING_OFF_CURRENCY" envDefault:"EUR"`
+	// Admin operations on ApprovalRoutes, given as "METHOD /route/:param", and those moving more than
+	// ApprovalAmount (in ApprovalCurrency) only run once a second admin approves them. Pending requests expire after ApprovalTTL. An empty amount sets no threshold.
+	ApprovalRoutes   []string      `env:"API_APPROVAL_ROUTES" envDefault:"DELETE /users/:id,PATCH /accounts/:accId" envSeparator:","`
+	ApprovalAmount   string        `env:"API_APPROVAL_AMOUNT" envDefault:"10000"`
+	ApprovalCurrency string        `env:"API_APPROVAL_CURRENCY" envDefault:"EUR"`
+	ApprovalTTL      time.Duration `env:"API_APPROVAL_TTL" envDefault:"24h"`
 }
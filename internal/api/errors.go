@@ -0,0 +1,87 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"net/http"
+)
+
+// errorStatus maps domain errors returned by the store to the HTTP status reported to the client.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrAccountNotFound),
+		errors.Is(err, ErrStandingOrderNotFound),
+		errors.Is(err, ErrHoldNotFound),
+		errors.Is(err, ErrTransactionNotFound),
+		errors.Is(err, ErrHolderNotFound),
+		errors.Is(err, ErrBeneficiaryNotFound),
+		errors.Is(err, ErrBatchNotFound),
+		errors.Is(err, ErrLoanNotFound),
+		errors.Is(err, ErrTermDepositNotFound),
+		errors.Is(err, ErrFeeRuleNotFound),
+		errors.Is(err, ErrFeeNotFound),
+		errors.Is(err, ErrApprovalNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrAccountNotActive),
+		errors.Is(err, ErrInvalidStatusTransition),
+		errors.Is(err, ErrAccountNotEmpty),
+		errors.Is(err, ErrAccountHasHolds),
+		errors.Is(err, ErrOpenAccountsPreventClose),
+		errors.Is(err, ErrUserDeactivated),
+		errors.Is(err, ErrStandingOrderNotActive),
+		errors.Is(err, ErrHoldNotActive),
+		errors.Is(err, ErrAlreadyReversed),
+		errors.Is(err, ErrCannotReverse),
+		errors.Is(err, ErrLastOwner),
+		errors.Is(err, ErrDuplicateBeneficiary),
+		errors.Is(err, ErrBatchNotPending),
+		errors.Is(err, ErrTermDepositNotActive),
+		errors.Is(err, ErrFeeAlreadyWaived),
+		errors.Is(err, ErrApprovalNotPending):
+		return http.StatusConflict
+	case errors.Is(err, ErrHolderNotAllowed),
+		errors.Is(err, ErrSelfApproval):
+		return http.StatusForbidden
+	case errors.Is(err, ErrInsufficientFunds),
+		errors.Is(err, ErrNoExchangeRate),
+		errors.Is(err, ErrCaptureExceedsHold),
+		errors.Is(err, ErrReversalExceedsOriginal),
+		errors.Is(err, ErrLimitExceeded),
+		errors.Is(err, ErrExternalBeneficiary),
+		errors.Is(err, ErrBeneficiaryCoolingOff),
+		errors.Is(err, ErrLoanAccount),
+		errors.Is(err, ErrTermDepositLocked):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrInvalidAmount),
+		errors.Is(err, ErrAmountOverflow),
+		errors.Is(err, ErrCurrencyMismatch),
+		errors.Is(err, ErrUnsupportedCurrency),
+		errors.Is(err, ErrSameAccount),
+		errors.Is(err, ErrInvalidAccountStatus),
+		errors.Is(err, ErrInvalidStatementPeriod),
+		errors.Is(err, ErrInvalidAccountType),
+		errors.Is(err, ErrInvalidInterestRate),
+		errors.Is(err, ErrInvalidSchedule),
+		errors.Is(err, ErrInvalidHoldDuration),
+		errors.Is(err, ErrInvalidOverdraftLimit),
+		errors.Is(err, ErrInvalidLimit),
+		errors.Is(err, ErrInvalidHolderRole),
+		errors.Is(err, ErrInvalidBeneficiary),
+		errors.Is(err, ErrInvalidAccountNumber),
+		errors.Is(err, ErrInvalidExchangeRate),
+		errors.Is(err, ErrInvalidBatchFile),
+		errors.Is(err, ErrEmptyPaymentBatch),
+		errors.Is(err, ErrInvalidLoan),
+		errors.Is(err, ErrInvalidTermDeposit),
+		errors.Is(err, ErrInvalidFeeRule),
+		errors.Is(err, ErrInvalidApprovalStatus):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func respondWithError(c *gin.Context, err error) {
+	c.JSON(errorStatus(err), Error{Error: err.Error()})
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Get Fee Schedule
+// @Description Admin-only. The fee rules in force, one per account type, operation, currency and tier
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Success 200 {array} types.FeeRule
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/fee-schedule [get]
+func (s *Server) handleGetFeeSchedule(c *gin.Context) {
+	rules, err := s.store.GetFeeSchedule()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Security ApiKeyAuth
+// @Summary Set Fee Rule
+// @Description Admin-only. Add a tier to the fee schedule, replacing the rule of the same tier. Operations of minAmount
+// @Description or more cost fixed plus rate of the amount; the tier with the highest minAmount not above the amount
+// @Description applies. Transfer and FX fees are tiered by the amount sent, monthly maintenance fees by the balance
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param accountType query string true "Account type the rule applies to" Enums(current, savings)
+// @Param operation query string true "Operation the fee is charged for" Enums(maintenance, transfer, fx)
+// @Param currency query string false "ISO 4217 currency of the accounts, EUR when omitted"
+// @Param minAmount query string false "Lower bound of the tier as a decimal string, 0 by default"
+// @Param fixed query string false "Fixed part of the fee as a decimal string, 0 by default"
+// @Param rate query string false "Variable part of the fee as a fraction of the amount, e.g. 0.001 for 0.1%"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.FeeRule
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/fee-schedule [post]
+func (s *Server) handleSetFeeRule(c *gin.Context) {
+	accountType, err := ParseAccountType(c.Query("accountType"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	operation, err := ParseFeeOperation(c.Query("operation"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	currency := DefaultCurrency
+	if c.Query("currency") != "" {
+		if currency, err = ParseCurrency(c.Query("currency")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+	}
+	minAmount, err := ParseMoney(c.DefaultQuery("minAmount", "0"), currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid minAmount"})
+		return
+	}
+	fixed, err := ParseMoney(c.DefaultQuery("fixed", "0"), currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid fixed fee"})
+		return
+	}
+
+	rule, err := NewFeeRule(accountType, operation, minAmount, fixed, c.DefaultQuery("rate", "0"), callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.SetFeeRule(rule); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Security ApiKeyAuth
+// @Summary Delete Fee Rule
+// @Description Admin-only. Remove a tier from the fee schedule. Fees already charged are not affected
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param ruleId path int true "Fee rule ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 204
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/fee-schedule/{ruleId} [delete]
+func (s *Server) handleDeleteFeeRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("ruleId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid fee rule ID"})
+		return
+	}
+	if err := s.store.DeleteFeeRule(id, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Fees
+// @Description The fees charged to an account in the half-open period [from, to), newest first. Each fee is also a
+// @Description line of its own in the statement. Plain dates cover the whole day; the period defaults to the current
+// @Description month up to now
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param from query string false "Start of the period, a date or RFC 3339 timestamp"
+// @Param to query string false "End of the period, a date or RFC 3339 timestamp"
+// @Success 200 {array} types.Fee
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/fees [get]
+func (s *Server) handleGetFees(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	from, to, err := parsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	fees, err := s.store.GetFees(accNum, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, fees)
+}
+
+// @Security ApiKeyAuth
+// @Summary Waive Fee
+// @Description Admin-only. Refund a fee to its account. The refund is booked as the reversal of the fee and the
+// @Description reason is kept with the fee and in the audit log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param feeId path int true "Fee ID"
+// @Param reason query string true "Why the fee is waived"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Fee
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The fee has already been waived"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /fees/{feeId}/waive [post]
+func (s *Server) handleWaiveFee(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("feeId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid fee ID"})
+		return
+	}
+	reason := c.Query("reason")
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, Error{Error: "A reason is required"})
+		return
+	}
+	fee, err := s.store.WaiveFee(id, reason, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, fee)
+}
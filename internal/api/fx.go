@@ -0,0 +1,94 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary List Exchange Rates
+// @Description Fetch every stored exchange rate, newest first per currency pair
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Success 200 {array} types.ExchangeRate
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /exchange-rates [get]
+func (s *Server) handleGetExchangeRates(c *gin.Context) {
+	rates, err := s.store.GetExchangeRates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+// @Security ApiKeyAuth
+// @Summary Load Exchange Rates
+// @Description Admin-only. Store exchange rates either from an uploaded CSV file (base,quote,rate,validFrom per line)
+// @Description or from a single rate given as query parameters
+// @Tags exchange-rates
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file false "CSV file with base,quote,rate,validFrom lines"
+// @Param base query string false "Base currency"
+// @Param quote query string false "Quote currency"
+// @Param rate query string false "Units of quote currency per unit of base currency"
+// @Param validFrom query string false "Date or RFC 3339 timestamp from which the rate applies, now when omitted"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {array} types.ExchangeRate
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /exchange-rates [post]
+func (s *Server) handleCreateExchangeRates(c *gin.Context) {
+	var rates []*ExchangeRate
+
+	if header, err := c.FormFile("file"); err == nil {
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Couldn't read uploaded file"})
+			return
+		}
+		defer file.Close()
+		rates, err = ParseExchangeRatesCSV(file)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+	} else {
+		base, err := ParseCurrency(c.Query("base"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid base currency"})
+			return
+		}
+		quote, err := ParseCurrency(c.Query("quote"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid quote currency"})
+			return
+		}
+		validFrom := time.Now()
+		if c.Query("validFrom") != "" {
+			validFrom, err = ParseDateOrTime(c.Query("validFrom"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, Error{Error: "Invalid validFrom"})
+				return
+			}
+		}
+		rate, err := NewExchangeRate(base, quote, c.Query("rate"), validFrom)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := s.store.SaveExchangeRates(rates); err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Create Hold
+// @Description Admin-only. Reserve funds on an account, e.g. for an authorized card payment. The hold reduces the
+// @Description available balance until it is captured, released or expires
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param amount query string true "Amount as a decimal string in the account currency"
+// @Param reference query string false "Reference of the authorization"
+// @Param expiresIn query string false "Lifetime of the hold as a Go duration, e.g. 72h"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Hold
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 422 {object} Error "Insufficient funds"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/holds [post]
+func (s *Server) handleCreateHold(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil || !account.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+
+	amount, err := ParseMoney(c.Query("amount"), account.Balance.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid amount"})
+		return
+	}
+	ttl := s.holdTTL
+	if c.Query("expiresIn") != "" {
+		if ttl, err = time.ParseDuration(c.Query("expiresIn")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid expiresIn"})
+			return
+		}
+	}
+
+	hold, err := NewHold(accNum, amount, c.Query("reference"), time.Now().Add(ttl), callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.CreateHold(hold); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Account Holds
+// @Description List the holds placed on an account, newest first
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param status query []string false "Only holds in these statuses" collectionFormat(multi)
+// @Success 200 {array} types.Hold
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/holds [get]
+func (s *Server) handleGetHolds(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	var statuses []HoldStatus
+	for _, status := range c.QueryArray("status") {
+		statuses = append(statuses, HoldStatus(status))
+	}
+	holds, err := s.store.GetHolds(accNum, statuses...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, holds)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Hold
+// @Description Admin-only. Fetch a hold by ID
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param holdId path int true "Hold ID"
+// @Success 200 {object} types.Hold
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Router /holds/{holdId} [get]
+func (s *Server) handleGetHold(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("holdId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid hold ID"})
+		return
+	}
+	hold, err := s.store.GetHold(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if hold == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such hold"})
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Security ApiKeyAuth
+// @Summary Capture Hold
+// @Description Admin-only. Settle part or all of a hold by posting it to the ledger. Without an amount
+// @Description everything still held is captured. A partially captured hold keeps reserving the rest.
+// @Description Expired holds can't be captured
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param holdId path int true "Hold ID"
+// @Param amount query string false "Amount to capture as a decimal string"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.HoldCapture
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Hold is no longer active or has expired"
+// @Failure 422 {object} Error "Capture exceeds the amount held"
+// @Router /holds/{holdId}/capture [post]
+func (s *Server) handleCaptureHold(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("holdId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid hold ID"})
+		return
+	}
+	hold, err := s.store.GetHold(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if hold == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such hold"})
+		return
+	}
+
+	amount := hold.Remaining()
+	if c.Query("amount") != "" {
+		if amount, err = ParseMoney(c.Query("amount"), hold.Amount.Currency); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid amount"})
+			return
+		}
+	}
+
+	hold, t, err := s.store.CaptureHold(id, amount)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, HoldCapture{Hold: hold, Transaction: t})
+}
+
+// @Security ApiKeyAuth
+// @Summary Release Hold
+// @Description Admin-only. End a hold without capturing what it still reserves
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param holdId path int true "Hold ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Hold
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Hold is no longer active"
+// @Router /holds/{holdId}/release [post]
+func (s *Server) handleReleaseHold(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("holdId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid hold ID"})
+		return
+	}
+	hold, err := s.store.ReleaseHold(id)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
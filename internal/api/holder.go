@@ -0,0 +1,136 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+)
+
+// @Security ApiKeyAuth
+// @Summary Get Account Holders
+// @Description List everyone with access to an account and their role, owners first
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {array} types.AccountHolder
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/holders [get]
+func (s *Server) handleGetAccountHolders(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	holders, err := s.store.GetAccountHolders(accNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, holders)
+}
+
+// @Security ApiKeyAuth
+// @Summary Add Account Holder
+// @Description Give another user access to an account, or change the role of an existing holder. Owners may move
+// @Description money and manage holders, signatories may move money and viewers may only look at the account.
+// @Description Only owners and admins may manage holders
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param userId query string true "User to add"
+// @Param role query string true "Role of the holder" Enums(owner, signatory, viewer)
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.AccountHolder
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The account would be left without an owner"
+// @Router /accounts/{id}/holders [post]
+func (s *Server) handleAddAccountHolder(c *gin.Context) {
+	if !canManageHolders(c) {
+		permissionDenied(c)
+		return
+	}
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	userID, err := parseUserID(c.Query("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid userId"})
+		return
+	}
+	role, err := ParseHolderRole(c.Query("role"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	user, err := s.store.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if user == nil || !user.Active {
+		c.JSON(http.StatusNotFound, Error{Error: "No such user"})
+		return
+	}
+
+	holder := NewAccountHolder(accNum, userID, role, callerFromContext(c).ID)
+	if err := s.store.SaveAccountHolder(holder, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, holder)
+}
+
+// @Security ApiKeyAuth
+// @Summary Remove Account Holder
+// @Description Take a user's access to an account away. Only owners and admins may remove holders, and the last
+// @Description owner can't be removed
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param userId path string true "Holder to remove"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {array} types.AccountHolder
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The account would be left without an owner"
+// @Router /accounts/{id}/holders/{userId} [delete]
+func (s *Server) handleRemoveAccountHolder(c *gin.Context) {
+	if !canManageHolders(c) {
+		permissionDenied(c)
+		return
+	}
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	userID, err := parseUserID(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid userId"})
+		return
+	}
+	if err := s.store.RemoveAccountHolder(accNum, userID, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	s.handleGetAccountHolders(c)
+}
+
+// canManageHolders reports whether the caller is an admin or an owner of the account of the route.
+func canManageHolders(c *gin.Context) bool {
+	if callerFromContext(c).Role == AdminRole {
+		return true
+	}
+	holder := holderFromContext(c)
+	return holder != nil && holder.CanManageHolders()
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/infrastructure/postgres"
+	. "go-bank-v2/internal/types"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const idempotencyHeader = "Idempotency-Key"
+
+// responseRecorder keeps a copy of everything written to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// withIdempotency makes a mutating handler safe to retry. The first response for an
+// Idempotency-Key is stored; replays with the same request get the stored response back,
+// while reusing the key for a different request is rejected. Requests without the header
+// are passed through unchanged.
+func withIdempotency(handlerFunc gin.HandlerFunc, s Store, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if key == "" {
+			handlerFunc(c)
+			return
+		}
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, Error{Error: "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Couldn't read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		callerID := ""
+		if caller := callerFromContext(c); caller != nil {
+			callerID = caller.ID
+		}
+
+		record := NewIdempotencyRecord(key, callerID, requestFingerprint(c, body), ttl)
+		existing, err := s.ClaimIdempotencyKey(record)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+			return
+		}
+		if existing != nil {
+			replayIdempotentResponse(c, existing, record.Fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		handlerFunc(c)
+
+		// Server errors are not remembered so the client can retry them.
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := s.ReleaseIdempotencyKey(record.Key, record.CallerID); err != nil {
+				log.Println(err)
+			}
+			return
+		}
+		record.StatusCode = recorder.Status()
+		record.Body = recorder.body.Bytes()
+		if err := s.CompleteIdempotencyKey(record); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func replayIdempotentResponse(c *gin.Context, record *IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, Error{Error: "Idempotency-Key was already used for a different request"})
+		return
+	}
+	if !record.Completed {
+		c.JSON(http.StatusConflict, Error{Error: "A request with this Idempotency-Key is still being processed"})
+		return
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(record.StatusCode, "application/json; charset=utf-8", record.Body)
+}
+
+// requestFingerprint identifies a request by method, path, query and body.
+func requestFingerprint(c *gin.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Set Interest Rate
+// @Description Admin-only. Set the annual interest rate an account earns from validFrom (today when omitted) onwards
+// @Tags interest
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param rate query string true "Annual rate as a fraction, e.g. 0.025 for 2.5%"
+// @Param validFrom query string false "Date or RFC 3339 timestamp the rate applies from"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.InterestRate
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/interest-rate [post]
+func (s *Server) handleSetInterestRate(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+	if !account.Payable() {
+		c.JSON(http.StatusBadRequest, Error{Error: "Only current and savings accounts earn interest"})
+		return
+	}
+
+	validFrom := Day(time.Now())
+	if c.Query("validFrom") != "" {
+		if validFrom, err = ParseDateOrTime(c.Query("validFrom")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid validFrom"})
+			return
+		}
+	}
+	rate, err := NewInterestRate(accNum, c.Query("rate"), validFrom)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.SaveInterestRate(rate, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Interest Accruals
+// @Description Interest accrued per day on an account. The period defaults to the current month
+// @Tags interest
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param from query string false "First day, a date or RFC 3339 timestamp"
+// @Param to query string false "Last day, a date or RFC 3339 timestamp"
+// @Success 200 {array} types.InterestAccrual
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/interest-accruals [get]
+func (s *Server) handleGetInterestAccruals(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	from, to, err := parsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+
+	accruals, err := s.store.GetInterestAccruals(accNum, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, accruals)
+}
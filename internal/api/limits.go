@@ -0,0 +1,143 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Get Limits
+// @Description The daily and monthly payout limits of a user and how much of them is used today and this month (UTC).
+// @Description Transfers to other users, withdrawals and card holds count towards the limits
+// @Tags limits
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} types.Limits
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /users/{id}/limits [get]
+func (s *Server) handleGetLimits(c *gin.Context) {
+	userID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return
+	}
+	limits, err := s.store.GetLimits(userID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
+
+// @Security ApiKeyAuth
+// @Summary Set Limits
+// @Description Admin-only. Give a user payout limits of their own in place of the defaults. A limit left out keeps
+// @Description its current value; 0 removes the limit
+// @Tags limits
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param daily query string false "Daily limit as a decimal string in the limit currency"
+// @Param monthly query string false "Monthly limit as a decimal string in the limit currency"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Limits
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /users/{id}/limits [post]
+func (s *Server) handleSetLimits(c *gin.Context) {
+	userID, ok := s.userFromPath(c)
+	if !ok {
+		return
+	}
+	current, err := s.store.GetLimits(userID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+
+	daily, monthly := current.Daily.Limit, current.Monthly.Limit
+	if c.Query("daily") != "" {
+		if daily, err = ParseMoney(c.Query("daily"), daily.Currency); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid daily limit"})
+			return
+		}
+	}
+	if c.Query("monthly") != "" {
+		if monthly, err = ParseMoney(c.Query("monthly"), monthly.Currency); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid monthly limit"})
+			return
+		}
+	}
+	userLimits, err := NewUserLimits(userID, daily, monthly)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.SetUserLimits(userLimits, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	s.respondWithLimits(c, userID)
+}
+
+// @Security ApiKeyAuth
+// @Summary Reset Limits
+// @Description Admin-only. Put a user back on the default payout limits
+// @Tags limits
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Limits
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /users/{id}/limits [delete]
+func (s *Server) handleResetLimits(c *gin.Context) {
+	userID, ok := s.userFromPath(c)
+	if !ok {
+		return
+	}
+	if err := s.store.ResetUserLimits(userID, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	s.respondWithLimits(c, userID)
+}
+
+// userFromPath returns the ID of the user named by the id parameter if that user exists.
+// It writes the error response itself.
+func (s *Server) userFromPath(c *gin.Context) (string, bool) {
+	userID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return "", false
+	}
+	user, err := s.store.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return "", false
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such user"})
+		return "", false
+	}
+	return userID, true
+}
+
+func (s *Server) respondWithLimits(c *gin.Context, userID string) {
+	limits, err := s.store.GetLimits(userID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+)
+
+// @Security ApiKeyAuth
+// @Summary Create Loan
+// @Description Admin-only. Lend money to the owner of the account. A loan account is opened for the debt, the
+// @Description principal is paid into the account and the instalments are collected from it as they fall due.
+// @Description Instalments that can't be collected fall into arrears, are charged the late fee once and are retried
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param principal query string true "Principal as a decimal string in the account currency"
+// @Param rate query string true "Annual interest rate as a decimal fraction, e.g. 0.05 for 5%"
+// @Param term query int true "Number of instalments"
+// @Param frequency query string false "Repayment frequency, monthly by default" Enums(weekly, monthly, quarterly)
+// @Param method query string false "Amortization method, annuity by default" Enums(annuity, linear)
+// @Param lateFee query string false "Fee charged once per instalment in arrears, as a decimal string in the account currency"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Loan
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The account is not active"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/loans [post]
+func (s *Server) handleCreateLoan(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil || !account.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+	currency := account.Balance.Currency
+
+	principal, err := ParseMoney(c.Query("principal"), currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid principal"})
+		return
+	}
+	term, err := strconv.Atoi(c.Query("term"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid term"})
+		return
+	}
+	frequency := MonthlyRepayment
+	if c.Query("frequency") != "" {
+		if frequency, err = ParseRepaymentFrequency(c.Query("frequency")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+	}
+	method := AnnuityAmortization
+	if c.Query("method") != "" {
+		if method, err = ParseAmortizationMethod(c.Query("method")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+			return
+		}
+	}
+	lateFee := Zero(currency)
+	if c.Query("lateFee") != "" {
+		if lateFee, err = ParseMoney(c.Query("lateFee"), currency); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid late fee"})
+			return
+		}
+	}
+
+	loan, err := NewLoan(account.OwnerID, accNum, principal, c.Query("rate"), term, frequency, method, lateFee)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.CreateLoan(loan, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, loan)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Loans
+// @Description List the loans repaid from an account, newest first, with their outstanding principal and arrears
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {array} types.Loan
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/loans [get]
+func (s *Server) handleGetLoans(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	loans, err := s.store.GetLoans(accNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, loans)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Loan
+// @Description Fetch a loan with its outstanding principal and arrears. The account is either the repayment account or
+// @Description the loan account
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param loanId path int true "Loan ID"
+// @Success 200 {object} types.Loan
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/loans/{loanId} [get]
+func (s *Server) handleGetLoan(c *gin.Context) {
+	loan, ok := s.loanFromPath(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, loan)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Loan Schedule
+// @Description The amortization schedule of a loan: every instalment with its due date, principal and interest parts,
+// @Description late fee and whether it has been paid
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param loanId path int true "Loan ID"
+// @Success 200 {array} types.LoanInstalment
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/loans/{loanId}/schedule [get]
+func (s *Server) handleGetLoanSchedule(c *gin.Context) {
+	loan, ok := s.loanFromPath(c)
+	if !ok {
+		return
+	}
+	instalments, err := s.store.GetLoanInstalments(loan.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, instalments)
+}
+
+// loanFromPath returns the loan named by the loanId parameter if the account of the route is its
+// repayment or loan account. It writes the error response itself.
+func (s *Server) loanFromPath(c *gin.Context) (*Loan, bool) {
+	id, err := strconv.ParseInt(c.Param("loanId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid loan ID"})
+		return nil, false
+	}
+	loan, err := s.store.GetLoan(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return nil, false
+	}
+	if loan == nil || (loan.RepaymentAccount != c.Param("accId") && loan.AccountNumber != c.Param("accId")) {
+		c.JSON(http.StatusNotFound, Error{Error: "Loan not found"})
+		return nil, false
+	}
+	return loan, true
+}
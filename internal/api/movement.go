@@ -0,0 +1,88 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+)
+
+// @Security ApiKeyAuth
+// @Summary Deposit
+// @Description Admin-only. Credit an account with money received by the bank
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param amount query string true "Amount as a decimal string in the account currency"
+// @Param reference query string false "External reference, e.g. a teller receipt number"
+// @Param reason query string false "Reason shown on the account history"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Transaction
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/deposits [post]
+func (s *Server) handleDeposit(c *gin.Context) {
+	s.handleCashMovement(c, NewDeposit)
+}
+
+// @Security ApiKeyAuth
+// @Summary Withdrawal
+// @Description Debit an account with money paid out by the bank. The balance may only go negative within the overdraft limit
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param amount query string true "Amount as a decimal string in the account currency"
+// @Param reference query string false "External reference"
+// @Param reason query string false "Reason shown on the account history"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Transaction
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 422 {object} Error "Insufficient funds"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/withdrawals [post]
+func (s *Server) handleWithdrawal(c *gin.Context) {
+	s.handleCashMovement(c, NewWithdrawal)
+}
+
+type cashMovementFunc func(accountNumber string, clearingAccount string, amount Money, reference string, reason string) (*Transaction, error)
+
+func (s *Server) handleCashMovement(c *gin.Context, newMovement cashMovementFunc) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil || !account.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+
+	amount, err := ParseMoney(c.Query("amount"), account.Balance.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid amount"})
+		return
+	}
+	clearing, err := s.store.GetSystemAccount(ClearingSystemAccount, account.Balance.Currency)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+
+	txn, err := newMovement(account.AccountNumber, clearing.AccountNumber, amount, c.Query("reference"), c.Query("reason"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	txn.InitiatedBy = callerFromContext(c).ID
+	if err := s.store.PostTransaction(txn); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, txn)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+)
+
+// @Security ApiKeyAuth
+// @Summary Set Overdraft Limit
+// @Description Admin-only. Let the account balance go below zero down to -limit. Lowering the limit below what is
+// @Description already drawn blocks further debits until the account is back within the limit
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param limit query string true "Limit as a decimal string in the account currency"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Account
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Account is closed"
+// @Router /accounts/{id}/overdraft [post]
+func (s *Server) handleSetOverdraftLimit(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil || !account.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+	limit, err := ParseMoney(c.Query("limit"), account.Balance.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid limit"})
+		return
+	}
+	s.setOverdraftLimit(c, accNum, limit)
+}
+
+// @Security ApiKeyAuth
+// @Summary Remove Overdraft Limit
+// @Description Admin-only. Remove the overdraft of an account. An overdrawn account takes no further debits until
+// @Description its balance is back to zero
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Account
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Account is closed"
+// @Router /accounts/{id}/overdraft [delete]
+func (s *Server) handleRemoveOverdraftLimit(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil || !account.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+	s.setOverdraftLimit(c, accNum, Zero(account.Balance.Currency))
+}
+
+func (s *Server) setOverdraftLimit(c *gin.Context, accNum string, limit Money) {
+	account, err := s.store.SetOverdraftLimit(accNum, limit, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
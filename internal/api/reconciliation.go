@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Security ApiKeyAuth
+// @Summary Get Reconciliation Run
+// @Description Admin-only. The outcome of a reconciliation run and every account whose stored balance differs from
+// @Description the sum of its ledger entries. A run still in progress lists the mismatches found so far
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param runId path int true "Run ID"
+// @Success 200 {object} types.ReconciliationRun
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /admin/reconciliation/{runId} [get]
+func (s *Server) handleGetReconciliationRun(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("runId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid run ID"})
+		return
+	}
+	run, err := s.store.GetReconciliationRun(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if run == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such reconciliation run"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+)
+
+// @Security ApiKeyAuth
+// @Summary Get Transaction
+// @Description Admin-only. Fetch a ledger transaction with all of its entries
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} types.Transaction
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Router /transactions/{id} [get]
+func (s *Server) handleGetTransaction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid transaction ID"})
+		return
+	}
+	t, err := s.store.GetTransaction(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if t == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such transaction"})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// @Security ApiKeyAuth
+// @Summary Reverse Transaction
+// @Description Admin-only. Book compensating entries for a transaction. Without an amount everything not yet
+// @Description reversed is reversed; a partial amount is given in the currency of the first entry and every entry
+// @Description is reversed in the same proportion. The reversal is linked to the original and shows up in statements.
+// @Description Fees, hold captures and loan and term deposit postings are undone by their own operations instead
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param amount query string false "Amount to reverse as a decimal string"
+// @Param reason query string true "Why the transaction is reversed"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Transaction
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Transaction already reversed or not reversible"
+// @Failure 422 {object} Error "Reversal exceeds the original"
+// @Router /transactions/{id}/reversal [post]
+func (s *Server) handleReverseTransaction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid transaction ID"})
+		return
+	}
+	reason := c.Query("reason")
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, Error{Error: "A reason is required"})
+		return
+	}
+	original, err := s.store.GetTransaction(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if original == nil || len(original.Entries) == 0 {
+		c.JSON(http.StatusNotFound, Error{Error: "No such transaction"})
+		return
+	}
+
+	var amount *Money
+	if c.Query("amount") != "" {
+		parsed, err := ParseMoney(c.Query("amount"), original.Entries[0].Amount.Currency)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid amount"})
+			return
+		}
+		amount = &parsed
+	}
+
+	reversal, err := s.store.ReverseTransaction(id, amount, reason, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reversal)
+}
@@ -2,16 +2,73 @@ package api
 
 import (
 	. "go-bank-v2/internal/infrastructure/postgres"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strings"
+	"time"
 )
 
 type Server struct {
-	listenAddr string
-	store      Store
+	listenAddr     string
+	store          Store
+	idempotencyTTL time.Duration
+	holdTTL        time.Duration
+	// termDepositRate and termDepositPenalty apply to the term deposits customers open themselves.
+	termDepositRate     string
+	termDepositPenalty  string
+	coolingOff          time.Duration
+	coolingOffThreshold Money
+	// approvalRoutes are the admin routes, keyed "METHOD /route/:param", that always need a second admin.
+	approvalRoutes map[string]bool
+	// approvalThreshold is the amount from which admin operations need a second admin; nil sets none.
+	approvalThreshold *Money
+	approvalTTL       time.Duration
+	// router replays approved requests.
+	router http.Handler
 }
 
-func NewServer(config RestApiConfig, store Store) *Server {
-	return &Server{
-		listenAddr: ":" + config.Port,
-		store:      store,
+func NewServer(config RestApiConfig, store Store) (*Server, error) {
+	s := &Server{
+		listenAddr:         ":" + config.Port,
+		store:              store,
+		idempotencyTTL:     config.IdempotencyTTL,
+		holdTTL:            config.HoldTTL,
+		termDepositRate:    config.TermDepositRate,
+		termDepositPenalty: config.TermDepositPenalty,
+		coolingOff:         config.CoolingOff,
+		approvalRoutes:     map[string]bool{},
+		approvalTTL:        config.ApprovalTTL,
 	}
+	if _, err := ParseInterestRate(s.termDepositRate); err != nil {
+		return nil, err
+	}
+	if _, err := ParseInterestRate(s.termDepositPenalty); err != nil {
+		return nil, err
+	}
+	if s.coolingOff > 0 {
+		currency, err := ParseCurrency(config.CoolingOffCurrency)
+		if err != nil {
+			return nil, err
+		}
+		if s.coolingOffThreshold, err = ParseMoney(config.CoolingOffAmount, currency); err != nil {
+			return nil, err
+		}
+	}
+	for _, route := range config.ApprovalRoutes {
+		if route = strings.TrimSpace(route); route != "" {
+			s.approvalRoutes[route] = true
+		}
+	}
+	if config.ApprovalAmount != "" {
+		currency, err := ParseCurrency(config.ApprovalCurrency)
+		if err != nil {
+			return nil, err
+		}
+		threshold, err := ParseMoney(config.ApprovalAmount, currency)
+		if err != nil {
+			return nil, err
+		}
+		s.approvalThreshold = &threshold
+	}
+	return s, nil
 }
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Create Standing Order
+// @Description Schedule a recurring transfer from one of the user's accounts. Monthly orders are paid on dayOfMonth,
+// @Description or on the last day of shorter months. The first payment is made on the first due date on or after startDate.
+// @Description Large orders to beneficiaries saved only recently are rejected until their cooling-off period is over
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param from query string true "Source account number"
+// @Param to query string true "Destination account number"
+// @Param amount query string true "Amount as a decimal string in the source account currency"
+// @Param reference query string false "Payment reference"
+// @Param frequency query string true "Recurrence" Enums(daily, weekly, monthly)
+// @Param dayOfMonth query int false "Day of month for monthly orders"
+// @Param startDate query string false "First possible payment date, today when omitted"
+// @Param endDate query string false "Last possible payment date"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.StandingOrder
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 422 {object} Error "Beneficiary in cooling-off"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /users/{id}/standing-orders [post]
+func (s *Server) handleCreateStandingOrder(c *gin.Context) {
+	ownerID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return
+	}
+	from, err := parseAccountNumber(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid source account"})
+		return
+	}
+	to, err := parseAccountNumber(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid destination account"})
+		return
+	}
+
+	source, err := s.store.GetAccountByNumber(from)
+	if err != nil || source == nil || !source.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+	if !s.canMoveMoney(ownerID, source.AccountNumber) {
+		c.JSON(http.StatusForbidden, Error{Error: ErrStandingOrderNotAllowed.Error()})
+		return
+	}
+	destination, err := s.store.GetAccountByNumber(to)
+	if err != nil || destination == nil || !destination.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such destination account"})
+		return
+	}
+
+	amount, err := ParseMoney(c.Query("amount"), source.Balance.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid amount"})
+		return
+	}
+	if err := s.checkCoolingOff(ownerID, destination.AccountNumber, amount); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	frequency, err := ParseFrequency(c.Query("frequency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	dayOfMonth := 0
+	if c.Query("dayOfMonth") != "" {
+		if dayOfMonth, err = strconv.Atoi(c.Query("dayOfMonth")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid dayOfMonth"})
+			return
+		}
+	}
+	startDate := time.Now()
+	if c.Query("startDate") != "" {
+		if startDate, err = ParseDateOrTime(c.Query("startDate")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid startDate"})
+			return
+		}
+	}
+	if Day(startDate).Before(Day(time.Now())) {
+		c.JSON(http.StatusBadRequest, Error{Error: "startDate is in the past"})
+		return
+	}
+	var endDate *time.Time
+	if c.Query("endDate") != "" {
+		end, err := ParseDateOrTime(c.Query("endDate"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "Invalid endDate"})
+			return
+		}
+		endDate = &end
+	}
+
+	order, err := NewStandingOrder(ownerID, from, to, amount, c.Query("reference"), frequency, dayOfMonth, startDate, endDate)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.CreateStandingOrder(order); err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Standing Orders
+// @Description List all standing orders of a user, including cancelled and completed ones
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} types.StandingOrder
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /users/{id}/standing-orders [get]
+func (s *Server) handleGetStandingOrders(c *gin.Context) {
+	ownerID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return
+	}
+	orders, err := s.store.GetStandingOrders(ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Security ApiKeyAuth
+// @Summary Cancel Standing Order
+// @Description Stop all future payments of a standing order. Past payments are kept
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param orderId path int true "Standing order ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.StandingOrder
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "Standing order is no longer active"
+// @Router /users/{id}/standing-orders/{orderId} [delete]
+func (s *Server) handleCancelStandingOrder(c *gin.Context) {
+	order, ok := s.standingOrderFromPath(c)
+	if !ok {
+		return
+	}
+	order, err := s.store.CancelStandingOrder(order.ID, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Standing Order Executions
+// @Description Outcome of every payment attempt of a standing order, oldest first
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param orderId path int true "Standing order ID"
+// @Success 200 {array} types.StandingOrderExecution
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Router /users/{id}/standing-orders/{orderId}/executions [get]
+func (s *Server) handleGetStandingOrderExecutions(c *gin.Context) {
+	order, ok := s.standingOrderFromPath(c)
+	if !ok {
+		return
+	}
+	executions, err := s.store.GetStandingOrderExecutions(order.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, executions)
+}
+
+// standingOrderFromPath loads the order named by the orderId parameter, making sure it belongs
+// to the user in the id parameter. It writes the error response itself.
+func (s *Server) standingOrderFromPath(c *gin.Context) (*StandingOrder, bool) {
+	ownerID, err := parseUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid ID"})
+		return nil, false
+	}
+	id, err := strconv.ParseInt(c.Param("orderId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid standing order ID"})
+		return nil, false
+	}
+	order, err := s.store.GetStandingOrder(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return nil, false
+	}
+	if order == nil || order.OwnerID != ownerID {
+		c.JSON(http.StatusNotFound, Error{Error: "No such standing order"})
+		return nil, false
+	}
+	return order, true
+}
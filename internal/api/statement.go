@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"go-bank-v2/internal/statement"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Summary Get Account Statement
+// @Description Opening balance, every movement with its running balance and closing balance of an account over a period.
+// @Description Plain dates cover the whole day; the period defaults to the current month up to now
+// @Tags account
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ofx
+// @Produce application/xml
+// @Produce text/plain
+// @Param id path string true "Account ID"
+// @Param from query string false "Start of the period, a date or RFC 3339 timestamp"
+// @Param to query string false "End of the period, a date or RFC 3339 timestamp"
+// @Param format query string false "Export format" Enums(json, csv, ofx, camt.053, mt940)
+// @Success 200 {object} types.Statement
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/statement [get]
+func (s *Server) handleGetStatement(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	format, err := statement.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	from, to, err := parsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+
+	st, err := s.store.GetStatement(accNum, from, to)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if st == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, format, st); err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if format != statement.JSON {
+		c.Header("Content-Disposition", `attachment; filename="`+format.FileName(st)+`"`)
+	}
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// parsePeriod reads a half-open [from, to) period. A plain date as end of the period includes that
+// whole day; missing bounds default to the start of the current month and now.
+func parsePeriod(fromStr string, toStr string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+	var err error
+	if fromStr != "" {
+		if from, err = ParseDateOrTime(fromStr); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatementPeriod
+		}
+	}
+	if toStr != "" {
+		if to, err = ParseDateOrTime(toStr); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatementPeriod
+		}
+		if !strings.Contains(toStr, "T") {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, ErrInvalidStatementPeriod
+	}
+	return from, to, nil
+}
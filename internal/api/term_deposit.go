@@ -0,0 +1,216 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+)
+
+// @Security ApiKeyAuth
+// @Summary Create Term Deposit
+// @Description Move money from the account onto a new term deposit account, locked until the maturity date term
+// @Description months from today. The fixed interest is paid at maturity together with the principal back into the
+// @Description account, or added to the principal for another term if the deposit rolls over. Customers get the
+// @Description bank's rate and penalty; only admins may set others
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param amount query string true "Principal as a decimal string in the account currency"
+// @Param rate query string false "Admin-only. Annual interest rate as a decimal fraction, e.g. 0.03 for 3%"
+// @Param term query int true "Term in months"
+// @Param penalty query string false "Admin-only. Part of the principal kept on early withdrawal as a decimal fraction"
+// @Param rollover query bool false "Start a new term at maturity instead of paying the deposit back"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.TermDeposit
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The account is not active"
+// @Failure 422 {object} Error "Insufficient funds"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/term-deposits [post]
+func (s *Server) handleCreateTermDeposit(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	account, err := s.store.GetAccountByNumber(accNum)
+	if err != nil || account == nil || !account.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+
+	amount, err := ParseMoney(c.Query("amount"), account.Balance.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid amount"})
+		return
+	}
+	term, err := strconv.Atoi(c.Query("term"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid term"})
+		return
+	}
+	rate, penalty := s.termDepositRate, s.termDepositPenalty
+	if c.Query("rate") != "" || c.Query("penalty") != "" {
+		if callerFromContext(c).Role != AdminRole {
+			permissionDenied(c)
+			return
+		}
+		rate, penalty = c.DefaultQuery("rate", rate), c.DefaultQuery("penalty", penalty)
+	}
+	rollover := false
+	if c.Query("rollover") != "" {
+		if rollover, err = strconv.ParseBool(c.Query("rollover")); err != nil {
+			c.JSON(http.StatusBadRequest, Error{Error: "rollover must be true or false"})
+			return
+		}
+	}
+
+	deposit, err := NewTermDeposit(account.OwnerID, accNum, amount, rate, term, penalty, rollover)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.CreateTermDeposit(deposit, callerFromContext(c).ID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deposit)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Term Deposits
+// @Description List the term deposits funded from an account, newest first
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {array} types.TermDeposit
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/term-deposits [get]
+func (s *Server) handleGetTermDeposits(c *gin.Context) {
+	accNum, err := parseAccountNumber(c.Param("accId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid Account Number"})
+		return
+	}
+	deposits, err := s.store.GetTermDeposits(accNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deposits)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Term Deposit
+// @Description Fetch a term deposit. The account is either the linked account or the term deposit account
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param depositId path int true "Term deposit ID"
+// @Success 200 {object} types.TermDeposit
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/term-deposits/{depositId} [get]
+func (s *Server) handleGetTermDeposit(c *gin.Context) {
+	deposit, ok := s.termDepositFromPath(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, deposit)
+}
+
+// @Security ApiKeyAuth
+// @Summary Withdraw Term Deposit
+// @Description Pay a term deposit back into the linked account before its maturity date. The interest of the running
+// @Description term is forfeited and the early-withdrawal penalty is taken from the principal
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param depositId path int true "Term deposit ID"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.TermDeposit
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The deposit is no longer active"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/term-deposits/{depositId}/withdraw [post]
+func (s *Server) handleWithdrawTermDeposit(c *gin.Context) {
+	deposit, ok := s.termDepositFromPath(c)
+	if !ok {
+		return
+	}
+	withdrawn, err := s.store.WithdrawTermDeposit(deposit.ID, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, withdrawn)
+}
+
+// @Security ApiKeyAuth
+// @Summary Set Term Deposit Rollover
+// @Description Choose whether an active term deposit starts a new term at maturity or is paid back into the linked
+// @Description account
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param depositId path int true "Term deposit ID"
+// @Param enabled query bool true "Roll the deposit over at maturity"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.TermDeposit
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 409 {object} Error "The deposit is no longer active"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /accounts/{id}/term-deposits/{depositId}/rollover [post]
+func (s *Server) handleSetTermDepositRollover(c *gin.Context) {
+	enabled, err := strconv.ParseBool(c.Query("enabled"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "enabled must be true or false"})
+		return
+	}
+	deposit, ok := s.termDepositFromPath(c)
+	if !ok {
+		return
+	}
+	updated, err := s.store.SetTermDepositRollover(deposit.ID, enabled, callerFromContext(c).ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// termDepositFromPath returns the deposit named by the depositId parameter if the account of the
+// route is its linked or term deposit account. It writes the error response itself.
+func (s *Server) termDepositFromPath(c *gin.Context) (*TermDeposit, bool) {
+	id, err := strconv.ParseInt(c.Param("depositId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid term deposit ID"})
+		return nil, false
+	}
+	deposit, err := s.store.GetTermDeposit(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return nil, false
+	}
+	if deposit == nil || (deposit.LinkedAccount != c.Param("accId") && deposit.AccountNumber != c.Param("accId")) {
+		c.JSON(http.StatusNotFound, Error{Error: "Term deposit not found"})
+		return nil, false
+	}
+	return deposit, true
+}
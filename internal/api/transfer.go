@@ -0,0 +1,149 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	. "go-bank-v2/internal/types"
+	"net/http"
+	"strconv"
+)
+
+// @Security ApiKeyAuth
+// @Summary Create Transfer
+// @Description Move money from an account the caller owns or signs for to another account atomically.
+// @Description When the accounts are held in different currencies the amount is converted at the rate valid at booking time.
+// @Description The destination is either an account number or one of the caller's beneficiaries; large transfers to
+// @Description recently saved beneficiaries are rejected until their cooling-off period is over
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param from query string true "Source account number"
+// @Param to query string false "Destination account number, required without beneficiaryId"
+// @Param beneficiaryId query int false "Saved beneficiary to pay instead of to"
+// @Param amount query string true "Amount as a decimal string in the source account currency"
+// @Param reference query string false "Payment reference, the beneficiary's default reference when omitted"
+// @Param Idempotency-Key header string false "Makes the request safe to retry"
+// @Success 200 {object} types.Transfer
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Failure 422 {object} Error "Insufficient funds, no exchange rate or beneficiary in cooling-off"
+// @Failure 500 {object} Error "Internal Server Error"
+// @Router /transfers [post]
+func (s *Server) handleCreateTransfer(c *gin.Context) {
+	caller := callerFromContext(c)
+
+	from, err := parseAccountNumber(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid source account"})
+		return
+	}
+	reference := c.Query("reference")
+	var to string
+	if c.Query("beneficiaryId") != "" {
+		b, ok := s.beneficiaryFromQuery(c, caller)
+		if !ok {
+			return
+		}
+		if b.External {
+			respondWithError(c, ErrExternalBeneficiary)
+			return
+		}
+		to = b.AccountNumber
+		if reference == "" {
+			reference = b.Reference
+		}
+	} else if to, err = parseAccountNumber(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid destination account"})
+		return
+	}
+
+	source, err := s.store.GetAccountByNumber(from)
+	if err != nil || source == nil || !source.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such account"})
+		return
+	}
+	if caller.Role != AdminRole && !s.canMoveMoney(caller.ID, source.AccountNumber) {
+		permissionDenied(c)
+		return
+	}
+	destination, err := s.store.GetAccountByNumber(to)
+	if err != nil || destination == nil || !destination.Payable() {
+		c.JSON(http.StatusNotFound, Error{Error: "No such destination account"})
+		return
+	}
+
+	amount, err := ParseMoney(c.Query("amount"), source.Balance.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid amount"})
+		return
+	}
+
+	if err := s.checkCoolingOff(caller.ID, destination.AccountNumber, amount); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	transfer, err := NewTransfer(source.AccountNumber, destination.AccountNumber, amount, reference, caller.ID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if err := s.store.CreateTransfer(transfer); err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, transfer)
+}
+
+// @Security ApiKeyAuth
+// @Summary Get Transfer
+// @Description Fetch a transfer by ID. Only the holders of the source or destination account may see it
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param transferId path int true "Transfer ID"
+// @Success 200 {object} types.Transfer
+// @Failure 400 {object} Error "Bad Request"
+// @Failure 403 {object} Error "Forbidden"
+// @Failure 404 {object} Error "Not Found"
+// @Router /transfers/{transferId} [get]
+func (s *Server) handleGetTransfer(c *gin.Context) {
+	caller := callerFromContext(c)
+
+	id, err := strconv.ParseInt(c.Param("transferId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Error{Error: "Invalid transfer ID"})
+		return
+	}
+	transfer, err := s.store.GetTransfer(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+	if transfer == nil {
+		c.JSON(http.StatusNotFound, Error{Error: "No such transfer"})
+		return
+	}
+
+	if caller.Role != AdminRole && !s.holdsAnyAccount(caller, transfer.FromAccount, transfer.ToAccount) {
+		permissionDenied(c)
+		return
+	}
+	c.JSON(http.StatusOK, transfer)
+}
+
+func (s *Server) holdsAnyAccount(user *User, accountNumbers ...string) bool {
+	for _, accNum := range accountNumbers {
+		holder, err := s.store.GetAccountHolder(accNum, user.ID)
+		if err == nil && holder != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// canMoveMoney reports whether the user holds the account in a role that may pay out of it.
+func (s *Server) canMoveMoney(userID string, accountNumber string) bool {
+	holder, err := s.store.GetAccountHolder(accountNumber, userID)
+	return err == nil && holder != nil && holder.CanMoveMoney()
+}
@@ -0,0 +1,56 @@
+// Package batch reads the bulk payment files customers upload.
+package batch
+
+import (
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"io"
+	"path"
+	"strings"
+)
+
+// MaxLines is the largest number of payments accepted in one file.
+const MaxLines = 10000
+
+var ErrUnsupportedFormat = errors.New("unsupported payment file format")
+
+var decoders = map[BatchFormat]func(io.Reader) ([]PaymentInstruction, error){
+	CSVBatch:     readCSV,
+	Pain001Batch: readPain001,
+}
+
+// ParseFormat returns the format with the given name. Without a name it is guessed from the file
+// name: XML files are taken to be pain.001, anything else CSV.
+func ParseFormat(s string, fileName string) (BatchFormat, error) {
+	if s == "" {
+		if strings.EqualFold(path.Ext(fileName), ".xml") {
+			return Pain001Batch, nil
+		}
+		return CSVBatch, nil
+	}
+	f := BatchFormat(strings.ToLower(s))
+	if _, ok := decoders[f]; !ok {
+		return "", errors.Wrapf(ErrUnsupportedFormat, "%q", s)
+	}
+	return f, nil
+}
+
+// Read parses the payments of a file. Only the structure of the file is checked here; whether the
+// payments can be made is up to the batch built from them.
+func Read(r io.Reader, f BatchFormat) ([]PaymentInstruction, error) {
+	decode, ok := decoders[f]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupportedFormat, "%q", f)
+	}
+	instructions, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(instructions) == 0 {
+		return nil, errors.Wrap(ErrInvalidBatchFile, "no payments in file")
+	}
+	if len(instructions) > MaxLines {
+		return nil, errors.Wrapf(ErrInvalidBatchFile, "more than %d payments in file", MaxLines)
+	}
+	return instructions, nil
+}
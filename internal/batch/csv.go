@@ -0,0 +1,49 @@
+package batch
+
+import (
+	"encoding/csv"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"io"
+	"strings"
+)
+
+// readCSV reads lines of the form account,amount[,reference[,name]]. A header line is optional.
+func readCSV(r io.Reader) ([]PaymentInstruction, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var instructions []PaymentInstruction
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidBatchFile, err.Error())
+		}
+		if line == 1 && strings.EqualFold(record[0], "account") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 4 {
+			return nil, errors.Wrapf(ErrInvalidBatchFile, "line %d: expected account,amount[,reference[,name]]", line)
+		}
+		in := PaymentInstruction{
+			Line:      line,
+			ToAccount: strings.TrimSpace(record[0]),
+			Amount:    strings.TrimSpace(record[1]),
+		}
+		if len(record) > 2 {
+			in.Reference = strings.TrimSpace(record[2])
+		}
+		if len(record) > 3 {
+			in.Name = strings.TrimSpace(record[3])
+		}
+		instructions = append(instructions, in)
+		if len(instructions) > MaxLines {
+			break
+		}
+	}
+	return instructions, nil
+}
@@ -0,0 +1,97 @@
+package batch
+
+import (
+	"encoding/xml"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ISO 20022 customer credit transfer initiation (pain.001). Elements are matched by local name,
+// so any version of the message whose structure is compatible is accepted.
+type painDocument struct {
+	Initiation struct {
+		Header struct {
+			MessageID    string `xml:"MsgId"`
+			Transactions string `xml:"NbOfTxs"`
+			ControlSum   string `xml:"CtrlSum"`
+		} `xml:"GrpHdr"`
+		PaymentInfos []painPaymentInfo `xml:"PmtInf"`
+	} `xml:"CstmrCdtTrfInitn"`
+}
+
+type painPaymentInfo struct {
+	Debtor    painAccount          `xml:"DbtrAcct"`
+	Transfers []painCreditTransfer `xml:"CdtTrfTxInf"`
+}
+
+type painAccount struct {
+	IBAN  string `xml:"Id>IBAN"`
+	Other string `xml:"Id>Othr>Id"`
+}
+
+// number returns the account identification, whichever way it was given.
+func (a painAccount) number() string {
+	if a.IBAN != "" {
+		return strings.TrimSpace(a.IBAN)
+	}
+	return strings.TrimSpace(a.Other)
+}
+
+type painCreditTransfer struct {
+	EndToEndID string `xml:"PmtId>EndToEndId"`
+	Amount     struct {
+		Value    string `xml:",chardata"`
+		Currency string `xml:"Ccy,attr"`
+	} `xml:"Amt>InstdAmt"`
+	Creditor     string      `xml:"Cdtr>Nm"`
+	CreditorAcct painAccount `xml:"CdtrAcct"`
+	Unstructured []string    `xml:"RmtInf>Ustrd"`
+}
+
+// readPain001 reads every credit transfer of every payment information block. Lines are numbered
+// by the position of the transfer in the file. The group header totals are checked when present.
+func readPain001(r io.Reader) ([]PaymentInstruction, error) {
+	var doc painDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(ErrInvalidBatchFile, err.Error())
+	}
+
+	var instructions []PaymentInstruction
+	sum := new(big.Rat)
+	for _, info := range doc.Initiation.PaymentInfos {
+		for _, t := range info.Transfers {
+			in := PaymentInstruction{
+				Line:          len(instructions) + 1,
+				DebtorAccount: info.Debtor.number(),
+				ToAccount:     t.CreditorAcct.number(),
+				Name:          strings.TrimSpace(t.Creditor),
+				Amount:        strings.TrimSpace(t.Amount.Value),
+				Currency:      Currency(strings.TrimSpace(t.Amount.Currency)),
+				Reference:     strings.TrimSpace(strings.Join(t.Unstructured, " ")),
+			}
+			if in.Reference == "" && t.EndToEndID != "NOTPROVIDED" {
+				in.Reference = strings.TrimSpace(t.EndToEndID)
+			}
+			if amount, ok := new(big.Rat).SetString(in.Amount); ok {
+				sum.Add(sum, amount)
+			}
+			instructions = append(instructions, in)
+		}
+	}
+
+	header := doc.Initiation.Header
+	if n := strings.TrimSpace(header.Transactions); n != "" && n != strconv.Itoa(len(instructions)) {
+		return nil, errors.Wrapf(ErrInvalidBatchFile, "NbOfTxs is %s but the file holds %d transfers", n, len(instructions))
+	}
+	if s := strings.TrimSpace(header.ControlSum); s != "" {
+		control, ok := new(big.Rat).SetString(s)
+		if !ok || control.Cmp(sum) != 0 {
+			return nil, errors.Wrapf(ErrInvalidBatchFile, "CtrlSum %s does not match the transfers", s)
+		}
+	}
+	return instructions, nil
+}
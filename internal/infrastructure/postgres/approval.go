@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"strconv"
+	"time"
+)
+
+const approvalRequestColumns = `ID, Method, Route, Path, Query, Body, ContentType, Amount, Currency, Status, MakerID, COALESCE(CheckerID, ''),
+                                COALESCE(Reason, ''), COALESCE(ResponseStatus, 0), Response, Expires, Created, Decided`
+
+func scanApprovalRequest(row rowScanner) (*ApprovalRequest, error) {
+	r := &ApprovalRequest{}
+	var body, response []byte
+	var amount sql.NullInt64
+	var currency sql.NullString
+	var decided sql.NullTime
+	err := row.Scan(
+		&r.ID,
+		&r.Method,
+		&r.Route,
+		&r.Path,
+		&r.Query,
+		&body,
+		&r.ContentType,
+		&amount,
+		&currency,
+		&r.Status,
+		&r.MakerID,
+		&r.CheckerID,
+		&r.Reason,
+		&r.ResponseStatus,
+		&response,
+		&r.Expires,
+		&r.Created,
+		&decided,
+	)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = string(body)
+	if len(response) > 0 {
+		r.Response = response
+	}
+	if amount.Valid {
+		r.Amount = &Money{Amount: amount.Int64, Currency: Currency(currency.String)}
+	}
+	if decided.Valid {
+		r.Decided = &decided.Time
+	}
+	return r, nil
+}
+
+// CreateApprovalRequest stores a request held back for a second admin.
+func (s *PostgresqlStore) CreateApprovalRequest(r *ApprovalRequest) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		var amount sql.NullInt64
+		var currency sql.NullString
+		if r.Amount != nil {
+			amount = sql.NullInt64{Int64: r.Amount.Amount, Valid: true}
+			currency = sql.NullString{String: string(r.Amount.Currency), Valid: true}
+		}
+		err := tx.QueryRow(
+			`INSERT INTO ApprovalRequest (Method, Route, Path, Query, Body, ContentType, Amount, Currency, Status, MakerID, Expires, Created)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+             RETURNING ID`,
+			r.Method,
+			r.Route,
+			r.Path,
+			r.Query,
+			[]byte(r.Body),
+			r.ContentType,
+			amount,
+			currency,
+			r.Status,
+			r.MakerID,
+			r.Expires,
+			r.Created,
+		).Scan(&r.ID)
+		if err != nil {
+			return err
+		}
+		return recordAuditEvent(tx, NewAuditEvent(r.MakerID, "request_approval", "approval_request", strconv.FormatInt(r.ID, 10), r.Operation()))
+	})
+}
+
+// GetApprovalRequest returns the approval request or nil.
+func (s *PostgresqlStore) GetApprovalRequest(id int64) (*ApprovalRequest, error) {
+	r, err := scanApprovalRequest(s.db.QueryRow(`SELECT `+approvalRequestColumns+` FROM ApprovalRequest WHERE ID = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetApprovalRequests lists the approval requests in any of the statuses, or all of them, newest first.
+func (s *PostgresqlStore) GetApprovalRequests(statuses ...ApprovalStatus) ([]*ApprovalRequest, error) {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	rows, err := s.db.Query(
+		`SELECT `+approvalRequestColumns+` FROM ApprovalRequest
+         WHERE cardinality($1::text[]) = 0 OR Status = ANY($1)
+         ORDER BY Created DESC, ID DESC`,
+		pq.Array(names),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*ApprovalRequest
+	for rows.Next() {
+		r, err := scanApprovalRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, r)
+	}
+	return requests, rows.Err()
+}
+
+// ApproveRequest marks a pending request as approved by the checker, who must not be its maker.
+// The caller executes the request afterwards and stores the outcome with CompleteApprovalRequest.
+func (s *PostgresqlStore) ApproveRequest(id int64, checkerID string) (*ApprovalRequest, error) {
+	return s.decideApprovalRequest(id, ApprovedRequest, checkerID, "")
+}
+
+// RejectRequest marks a pending request as rejected by the checker, who must not be its maker. The
+// request is never executed.
+func (s *PostgresqlStore) RejectRequest(id int64, checkerID string, reason string) (*ApprovalRequest, error) {
+	return s.decideApprovalRequest(id, RejectedRequest, checkerID, reason)
+}
+
+func (s *PostgresqlStore) decideApprovalRequest(id int64, status ApprovalStatus, checkerID string, reason string) (*ApprovalRequest, error) {
+	var request *ApprovalRequest
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		request, err = scanApprovalRequest(tx.QueryRow(`SELECT `+approvalRequestColumns+` FROM ApprovalRequest WHERE ID = $1 FOR UPDATE`, id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrApprovalNotFound, "%d", id)
+			}
+			return err
+		}
+		now := time.Now()
+		if err := request.CheckDecidable(checkerID, now); err != nil {
+			return err
+		}
+		request.Decide(status, checkerID, reason, now)
+		if err := updateApprovalRequest(tx, request); err != nil {
+			return err
+		}
+		action := "approve_request"
+		details := request.Operation()
+		if status == RejectedRequest {
+			action = "reject_request"
+			details += ": " + reason
+		}
+		return recordAuditEvent(tx, NewAuditEvent(checkerID, action, "approval_request", strconv.FormatInt(id, 10), details))
+	})
+	return request, err
+}
+
+// CompleteApprovalRequest stores the response an approved request was executed with.
+func (s *PostgresqlStore) CompleteApprovalRequest(r *ApprovalRequest) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`UPDATE ApprovalRequest SET ResponseStatus = $1, Response = $2 WHERE ID = $3`,
+			r.ResponseStatus,
+			[]byte(r.Response),
+			r.ID,
+		)
+		if err != nil {
+			return err
+		}
+		details := r.Operation() + " returned " + strconv.Itoa(r.ResponseStatus)
+		return recordAuditEvent(tx, NewAuditEvent(r.CheckerID, "execute_request", "approval_request", strconv.FormatInt(r.ID, 10), details))
+	})
+}
+
+// ExpireApprovalRequests ends up to limit pending requests that expired before now and returns how
+// many it ended. Requests locked by a concurrent decision are left for the next run.
+func (s *PostgresqlStore) ExpireApprovalRequests(now time.Time, limit int) (int, error) {
+	expired := 0
+	err := s.withTx(func(tx *sql.Tx) error {
+		rows, err := tx.Query(
+			`SELECT `+approvalRequestColumns+` FROM ApprovalRequest
+             WHERE Status = $1 AND Expires <= $2
+             ORDER BY Expires, ID
+             LIMIT $3
+             FOR UPDATE SKIP LOCKED`,
+			PendingRequest,
+			now,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		var requests []*ApprovalRequest
+		for rows.Next() {
+			r, err := scanApprovalRequest(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			requests = append(requests, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, r := range requests {
+			r.Decide(ExpiredRequest, "", "", now)
+			if err := updateApprovalRequest(tx, r); err != nil {
+				return err
+			}
+			event := NewAuditEvent(SystemActor, "expire_request", "approval_request", strconv.FormatInt(r.ID, 10), r.Operation())
+			if err := recordAuditEvent(tx, event); err != nil {
+				return err
+			}
+		}
+		expired = len(requests)
+		return nil
+	})
+	return expired, err
+}
+
+func updateApprovalRequest(tx *sql.Tx, r *ApprovalRequest) error {
+	_, err := tx.Exec(
+		`UPDATE ApprovalRequest SET Status = $1, CheckerID = NULLIF($2, ''), Reason = NULLIF($3, ''), Decided = $4 WHERE ID = $5`,
+		r.Status,
+		r.CheckerID,
+		r.Reason,
+		r.Decided,
+		r.ID,
+	)
+	return err
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"database/sql"
+	. "go-bank-v2/internal/types"
+)
+
+func (s *PostgresqlStore) RecordAuditEvent(event *AuditEvent) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return recordAuditEvent(tx, event)
+	})
+}
+
+func recordAuditEvent(tx *sql.Tx, event *AuditEvent) error {
+	return tx.QueryRow(
+		`INSERT INTO AuditEvent (ActorID, Action, Entity, EntityID, Details, Created)
+         VALUES ($1, $2, $3, $4, $5, $6)
+         RETURNING ID`,
+		event.ActorID,
+		event.Action,
+		event.Entity,
+		event.EntityID,
+		event.Details,
+		event.Created,
+	).Scan(&event.ID)
+}
+
+func (s *PostgresqlStore) GetAuditEvents(entity string, entityID string) ([]*AuditEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT ID, ActorID, Action, Entity, EntityID, Details, Created
+         FROM AuditEvent WHERE Entity = $1 AND EntityID = $2 ORDER BY Created, ID`,
+		entity,
+		entityID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*AuditEvent
+	for rows.Next() {
+		event := &AuditEvent{}
+		err := rows.Scan(
+			&event.ID,
+			&event.ActorID,
+			&event.Action,
+			&event.Entity,
+			&event.EntityID,
+			&event.Details,
+			&event.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
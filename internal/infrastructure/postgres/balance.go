@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+// GetBalanceAt returns the balance of an account as implied by the entries booked before at,
+// or nil if the account does not exist.
+func (s *PostgresqlStore) GetBalanceAt(accountNumber string, at time.Time) (*Money, error) {
+	var currency Currency
+	err := s.db.QueryRow(`SELECT Currency FROM Account WHERE AccountNumber = $1`, accountNumber).Scan(&currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	balance, err := balanceAt(s.db, accountNumber, currency, at)
+	if err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// balanceAt starts from the latest balance snapshot taken at or before at and adds the entries
+// booked since, so only the movements after the snapshot are read however old the account is.
+func balanceAt(q querier, accountNumber string, currency Currency, at time.Time) (Money, error) {
+	balance := Zero(currency)
+	err := q.QueryRow(
+		`SELECT COALESCE(s.Balance, 0) + COALESCE((
+                    SELECT SUM(CASE WHEN e.Direction = 'credit' THEN e.Amount ELSE -e.Amount END)
+                    FROM LedgerEntry e
+                    WHERE e.AccountNumber = $1 AND e.Created >= COALESCE(s.At, '-infinity') AND e.Created < $2
+                ), 0)
+         FROM (SELECT 1) one
+         LEFT JOIN LATERAL (
+             SELECT At, Balance FROM BalanceSnapshot
+             WHERE AccountNumber = $1 AND At <= $2
+             ORDER BY At DESC
+             LIMIT 1
+         ) s ON true`,
+		accountNumber,
+		at,
+	).Scan(&balance.Amount)
+	return balance, err
+}
+
+// SnapshotBalances records the balance at the given time of up to limit accounts that have entries
+// booked since their last snapshot, and returns how many it recorded. Accounts that did not move
+// need no new snapshot. Each snapshot is a single statement, so no account is locked.
+func (s *PostgresqlStore) SnapshotBalances(at time.Time, limit int) (int, error) {
+	rows, err := s.db.Query(
+		`SELECT a.AccountNumber, a.Currency
+         FROM Account a
+         LEFT JOIN LATERAL (SELECT max(At) AS At FROM BalanceSnapshot WHERE AccountNumber = a.AccountNumber) last ON true
+         WHERE COALESCE(last.At, '-infinity') < $1
+           AND EXISTS (
+               SELECT 1 FROM LedgerEntry e
+               WHERE e.AccountNumber = a.AccountNumber AND e.Created >= COALESCE(last.At, '-infinity') AND e.Created < $1
+           )
+         ORDER BY a.AccountNumber
+         LIMIT $2`,
+		at,
+		limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+	type account struct {
+		number   string
+		currency Currency
+	}
+	var accounts []account
+	for rows.Next() {
+		var a account
+		if err := rows.Scan(&a.number, &a.currency); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		accounts = append(accounts, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, a := range accounts {
+		balance, err := balanceAt(s.db, a.number, a.currency, at)
+		if err != nil {
+			return 0, err
+		}
+		_, err = s.db.Exec(
+			`INSERT INTO BalanceSnapshot (AccountNumber, At, Balance, Currency, Created)
+             VALUES ($1, $2, $3, $4, $5)
+             ON CONFLICT (AccountNumber, At) DO NOTHING`,
+			a.number,
+			at,
+			balance.Amount,
+			balance.Currency,
+			time.Now(),
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(accounts), nil
+}
@@ -0,0 +1,309 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+const paymentBatchColumns = `ID, AccountNumber, Format, Status, Total, Currency, ValidLines, InvalidLines, CreatedBy, Created, Executed`
+
+func scanPaymentBatch(row rowScanner) (*PaymentBatch, error) {
+	b := &PaymentBatch{}
+	var executed sql.NullTime
+	err := row.Scan(
+		&b.ID,
+		&b.AccountNumber,
+		&b.Format,
+		&b.Status,
+		&b.Total.Amount,
+		&b.Total.Currency,
+		&b.ValidLines,
+		&b.InvalidLines,
+		&b.CreatedBy,
+		&b.Created,
+		&executed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if executed.Valid {
+		b.Executed = &executed.Time
+	}
+	return b, nil
+}
+
+const paymentLineColumns = `ID, BatchID, Line, ToAccount, Name, Amount, Currency, Reference, Status, Error, COALESCE(TransferID, 0)`
+
+func scanPaymentLine(row rowScanner) (*PaymentLine, error) {
+	l := &PaymentLine{}
+	err := row.Scan(
+		&l.ID,
+		&l.BatchID,
+		&l.Line,
+		&l.ToAccount,
+		&l.Name,
+		&l.Amount.Amount,
+		&l.Amount.Currency,
+		&l.Reference,
+		&l.Status,
+		&l.Error,
+		&l.TransferID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// CreatePaymentBatch validates the lines against the accounts they pay into and the funds of the
+// paying account, and stores the batch with the outcome of every line for preview. Nothing is
+// booked until the batch is executed.
+func (s *PostgresqlStore) CreatePaymentBatch(batch *PaymentBatch) error {
+	account, err := s.GetAccountByNumber(batch.AccountNumber)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errors.Wrapf(ErrAccountNotFound, "%s", batch.AccountNumber)
+	}
+	if err := account.CheckCanPost(); err != nil {
+		return err
+	}
+	if err := s.checkDestinations(batch); err != nil {
+		return err
+	}
+	if err := batch.CheckFunds(account); err != nil {
+		return err
+	}
+	if err := batch.Summarise(); err != nil {
+		return err
+	}
+
+	return s.withTx(func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`INSERT INTO PaymentBatch (AccountNumber, Format, Status, Total, Currency, ValidLines, InvalidLines, CreatedBy, Created)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+             RETURNING ID`,
+			batch.AccountNumber,
+			batch.Format,
+			batch.Status,
+			batch.Total.Amount,
+			batch.Total.Currency,
+			batch.ValidLines,
+			batch.InvalidLines,
+			batch.CreatedBy,
+			batch.Created,
+		).Scan(&batch.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range batch.Lines {
+			line.BatchID = batch.ID
+			err := tx.QueryRow(
+				`INSERT INTO PaymentLine (BatchID, Line, ToAccount, Name, Amount, Currency, Reference, Status, Error)
+                 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                 RETURNING ID`,
+				line.BatchID,
+				line.Line,
+				line.ToAccount,
+				line.Name,
+				line.Amount.Amount,
+				line.Amount.Currency,
+				line.Reference,
+				line.Status,
+				line.Error,
+			).Scan(&line.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return recordAuditEvent(tx, NewAuditEvent(batch.CreatedBy, "create_payment_batch", "account", batch.AccountNumber, batch.Total.Format()))
+	})
+}
+
+// checkDestinations invalidates the lines paying into anything but an open customer account of
+// this bank. Batches can't pay out to other banks.
+func (s *PostgresqlStore) checkDestinations(batch *PaymentBatch) error {
+	var numbers []string
+	for _, line := range batch.Lines {
+		if line.Status == ValidLine {
+			numbers = append(numbers, line.ToAccount)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.Query(
+		`SELECT AccountNumber FROM Account WHERE AccountNumber = ANY($1) AND Type IN ($2, $3) AND Status <> $4`,
+		pq.Array(numbers),
+		CurrentAccount,
+		SavingsAccount,
+		ClosedAccount,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	open := make(map[string]bool)
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			return err
+		}
+		open[number] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, line := range batch.Lines {
+		if line.Status == ValidLine && !open[line.ToAccount] {
+			line.Invalidate(errors.Wrapf(ErrAccountNotFound, "%s", line.ToAccount))
+		}
+	}
+	return nil
+}
+
+// GetPaymentBatch returns the batch with its lines, or nil.
+func (s *PostgresqlStore) GetPaymentBatch(id int64) (*PaymentBatch, error) {
+	batch, err := scanPaymentBatch(s.db.QueryRow(`SELECT `+paymentBatchColumns+` FROM PaymentBatch WHERE ID = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if batch.Lines, err = getPaymentLines(s.db, id); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// GetPaymentBatches lists the batches uploaded for an account, newest first, without their lines.
+func (s *PostgresqlStore) GetPaymentBatches(accountNumber string) ([]*PaymentBatch, error) {
+	rows, err := s.db.Query(
+		`SELECT `+paymentBatchColumns+` FROM PaymentBatch WHERE AccountNumber = $1 ORDER BY Created DESC, ID DESC`,
+		accountNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []*PaymentBatch
+	for rows.Next() {
+		batch, err := scanPaymentBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	return batches, rows.Err()
+}
+
+func getPaymentLines(q querier, batchID int64) ([]*PaymentLine, error) {
+	rows, err := q.Query(`SELECT `+paymentLineColumns+` FROM PaymentLine WHERE BatchID = $1 ORDER BY Line, ID`, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []*PaymentLine
+	for rows.Next() {
+		line, err := scanPaymentLine(rows)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// ExecutePaymentBatch makes the transfers of the valid lines in file order, all in one database
+// transaction so a batch is executed at most once. A line the bank rejects (e.g. because the funds
+// ran out since the preview) is marked failed and does not stop the others.
+func (s *PostgresqlStore) ExecutePaymentBatch(id int64, actorID string) (*PaymentBatch, error) {
+	var batch *PaymentBatch
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		batch, err = scanPaymentBatch(tx.QueryRow(`SELECT `+paymentBatchColumns+` FROM PaymentBatch WHERE ID = $1 FOR UPDATE`, id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrBatchNotFound, "%d", id)
+			}
+			return err
+		}
+		if batch.Status != PendingBatch {
+			return errors.Wrapf(ErrBatchNotPending, "%d", id)
+		}
+		if batch.ValidLines == 0 {
+			return errors.Wrapf(ErrEmptyPaymentBatch, "%d", id)
+		}
+		if batch.Lines, err = getPaymentLines(tx, id); err != nil {
+			return err
+		}
+
+		for _, line := range batch.Lines {
+			if line.Status != ValidLine {
+				continue
+			}
+			if err := s.payLine(tx, batch, line, actorID); err != nil {
+				return err
+			}
+			_, err := tx.Exec(
+				`UPDATE PaymentLine SET Status = $1, Error = $2, TransferID = NULLIF($3, 0) WHERE ID = $4`,
+				line.Status,
+				line.Error,
+				line.TransferID,
+				line.ID,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		batch.Status = ExecutedBatch
+		batch.Executed = &now
+		if _, err := tx.Exec(`UPDATE PaymentBatch SET Status = $1, Executed = $2 WHERE ID = $3`, batch.Status, now, id); err != nil {
+			return err
+		}
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "execute_payment_batch", "account", batch.AccountNumber, batch.Total.Format()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// payLine makes the transfer of one line and records the outcome on it. Only technical failures
+// are returned.
+func (s *PostgresqlStore) payLine(tx *sql.Tx, batch *PaymentBatch, line *PaymentLine, actorID string) error {
+	if _, err := tx.Exec(`SAVEPOINT payment`); err != nil {
+		return err
+	}
+	transfer, err := NewTransfer(batch.AccountNumber, line.ToAccount, line.Amount, line.Reference, actorID)
+	if err == nil {
+		err = s.createTransfer(tx, transfer)
+	}
+	switch {
+	case err == nil:
+		line.Status = SucceededLine
+		line.TransferID = transfer.ID
+	case isPaymentRejection(err):
+		if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT payment`); err != nil {
+			return err
+		}
+		line.Status = FailedLine
+		line.Error = err.Error()
+	default:
+		return err
+	}
+	return nil
+}
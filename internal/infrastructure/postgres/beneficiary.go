@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+const beneficiaryColumns = `ID, UserID, Nickname, AccountNumber, Reference, External, Created`
+
+func scanBeneficiary(row rowScanner) (*Beneficiary, error) {
+	b := &Beneficiary{}
+	err := row.Scan(&b.ID, &b.UserID, &b.Nickname, &b.AccountNumber, &b.Reference, &b.External, &b.Created)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// CreateBeneficiary saves a payee for the user. Account numbers of this bank must belong to an open
+// customer account; any other valid account number is saved as an external beneficiary.
+func (s *PostgresqlStore) CreateBeneficiary(b *Beneficiary) error {
+	b.External = !IsBankAccountNumber(b.AccountNumber, s.accountPrefix)
+	if !b.External {
+		account, err := s.GetAccountByNumber(b.AccountNumber)
+		if err != nil {
+			return err
+		}
+		if account == nil || !account.Payable() || account.Status == ClosedAccount {
+			return errors.Wrapf(ErrAccountNotFound, "%s", b.AccountNumber)
+		}
+	}
+
+	err := s.db.QueryRow(
+		`INSERT INTO Beneficiary (UserID, Nickname, AccountNumber, Reference, External, Created)
+         VALUES ($1, $2, $3, $4, $5, $6)
+         ON CONFLICT (UserID, AccountNumber) WHERE Deleted IS NULL DO NOTHING
+         RETURNING ID`,
+		b.UserID,
+		b.Nickname,
+		b.AccountNumber,
+		b.Reference,
+		b.External,
+		b.Created,
+	).Scan(&b.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Wrapf(ErrDuplicateBeneficiary, "%s", b.AccountNumber)
+	}
+	return err
+}
+
+// GetBeneficiary returns a beneficiary that has not been deleted, or nil.
+func (s *PostgresqlStore) GetBeneficiary(id int64) (*Beneficiary, error) {
+	b, err := scanBeneficiary(s.db.QueryRow(`SELECT `+beneficiaryColumns+` FROM Beneficiary WHERE ID = $1 AND Deleted IS NULL`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// LastSavedBeneficiary returns the beneficiary the user saved most recently for an account number,
+// even if it has been removed since, or nil if they never saved one.
+func (s *PostgresqlStore) LastSavedBeneficiary(userID string, accountNumber string) (*Beneficiary, error) {
+	b, err := scanBeneficiary(s.db.QueryRow(
+		`SELECT `+beneficiaryColumns+` FROM Beneficiary WHERE UserID = $1 AND AccountNumber = $2
+         ORDER BY Created DESC, ID DESC LIMIT 1`,
+		userID,
+		accountNumber,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetBeneficiaries lists the user's beneficiaries by nickname.
+func (s *PostgresqlStore) GetBeneficiaries(userID string) ([]*Beneficiary, error) {
+	rows, err := s.db.Query(
+		`SELECT `+beneficiaryColumns+` FROM Beneficiary WHERE UserID = $1 AND Deleted IS NULL ORDER BY lower(Nickname), ID`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var beneficiaries []*Beneficiary
+	for rows.Next() {
+		b, err := scanBeneficiary(rows)
+		if err != nil {
+			return nil, err
+		}
+		beneficiaries = append(beneficiaries, b)
+	}
+	return beneficiaries, rows.Err()
+}
+
+// DeleteBeneficiary removes a beneficiary from the user's list. The record is kept.
+func (s *PostgresqlStore) DeleteBeneficiary(id int64, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		var userID, accountNumber string
+		err := tx.QueryRow(
+			`UPDATE Beneficiary SET Deleted = $1 WHERE ID = $2 AND Deleted IS NULL RETURNING UserID, AccountNumber`,
+			time.Now(),
+			id,
+		).Scan(&userID, &accountNumber)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrBeneficiaryNotFound, "%d", id)
+			}
+			return err
+		}
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "delete_beneficiary", "user", userID, accountNumber))
+	})
+}
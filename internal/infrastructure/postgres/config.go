@@ -6,4 +6,11 @@ type DbConfig struct {
 	Database string `env:"DB_DATABASE"`
 	Username string `env:"DB_USERNAME"`
 	Password string `env:"DB_PASSWORD"`
+	// AccountPrefix is the bank code every account number starts with.
+	AccountPrefix string `env:"BANK_ACCOUNT_PREFIX" envDefault:"1001"`
+	// DailyLimit and MonthlyLimit cap what a user may pay out, in LimitCurrency, unless an admin
+	// gave them limits of their own. Zero means no limit.
+	DailyLimit    string `env:"LIMITS_DAILY" envDefault:"0"`
+	MonthlyLimit  string `env:"LIMITS_MONTHLY" envDefault:"0"`
+	LimitCurrency string `env:"LIMITS_CURRENCY" envDefault:"EUR"`
 }
@@ -0,0 +1,396 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"strconv"
+	"time"
+)
+
+const feeRuleColumns = `ID, AccountType, Operation, Currency, MinAmount, Fixed, Rate, CreatedBy, Created`
+
+func scanFeeRule(row rowScanner) (*FeeRule, error) {
+	r := &FeeRule{}
+	err := row.Scan(&r.ID, &r.AccountType, &r.Operation, &r.MinAmount.Currency, &r.MinAmount.Amount, &r.Fixed.Amount, &r.Rate, &r.CreatedBy, &r.Created)
+	if err != nil {
+		return nil, err
+	}
+	r.Fixed.Currency = r.MinAmount.Currency
+	return r, nil
+}
+
+// SetFeeRule adds a tier to the fee schedule, replacing the rule of the same tier if there is one.
+// Fees already charged keep the rule they were calculated with.
+func (s *PostgresqlStore) SetFeeRule(rule *FeeRule) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`UPDATE FeeRule SET Deleted = $1
+             WHERE AccountType = $2 AND Operation = $3 AND Currency = $4 AND MinAmount = $5 AND Deleted IS NULL`,
+			rule.Created,
+			rule.AccountType,
+			rule.Operation,
+			rule.MinAmount.Currency,
+			rule.MinAmount.Amount,
+		)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow(
+			`INSERT INTO FeeRule (AccountType, Operation, Currency, MinAmount, Fixed, Rate, CreatedBy, Created)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+             RETURNING ID`,
+			rule.AccountType,
+			rule.Operation,
+			rule.MinAmount.Currency,
+			rule.MinAmount.Amount,
+			rule.Fixed.Amount,
+			rule.Rate,
+			rule.CreatedBy,
+			rule.Created,
+		).Scan(&rule.ID)
+		if err != nil {
+			return err
+		}
+		details := fmt.Sprintf("%s %s from %s: %s + %s", rule.AccountType, rule.Operation, rule.MinAmount.Format(), rule.Fixed.Format(), rule.Rate)
+		return recordAuditEvent(tx, NewAuditEvent(rule.CreatedBy, "set_fee_rule", "fee_rule", strconv.FormatInt(rule.ID, 10), details))
+	})
+}
+
+// DeleteFeeRule removes a tier from the fee schedule. The record is kept.
+func (s *PostgresqlStore) DeleteFeeRule(id int64, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE FeeRule SET Deleted = $1 WHERE ID = $2 AND Deleted IS NULL`, time.Now(), id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			if err != nil {
+				return err
+			}
+			return errors.Wrapf(ErrFeeRuleNotFound, "%d", id)
+		}
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "delete_fee_rule", "fee_rule", strconv.FormatInt(id, 10), ""))
+	})
+}
+
+// GetFeeSchedule lists the rules in force by account type, operation, currency and tier.
+func (s *PostgresqlStore) GetFeeSchedule() ([]*FeeRule, error) {
+	rows, err := s.db.Query(
+		`SELECT ` + feeRuleColumns + ` FROM FeeRule WHERE Deleted IS NULL ORDER BY AccountType, Operation, Currency, MinAmount`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*FeeRule
+	for rows.Next() {
+		r, err := scanFeeRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// getFeeRule returns the tier of the schedule an operation of amount falls into, or nil if it
+// is free.
+func getFeeRule(q querier, accountType AccountType, operation FeeOperation, amount Money) (*FeeRule, error) {
+	r, err := scanFeeRule(q.QueryRow(
+		`SELECT `+feeRuleColumns+` FROM FeeRule
+         WHERE AccountType = $1 AND Operation = $2 AND Currency = $3 AND MinAmount <= $4 AND Deleted IS NULL
+         ORDER BY MinAmount DESC LIMIT 1`,
+		accountType,
+		operation,
+		amount.Currency,
+		amount.Abs().Amount,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+const feeColumns = `ID, AccountNumber, Operation, COALESCE(RuleID, 0), Basis, Amount, Currency, COALESCE(TransferID, 0), Period, Status,
+                    COALESCE(TransactionID, 0), COALESCE(WaivedBy, ''), COALESCE(WaiverReason, ''),
+                    COALESCE(WaiverTransactionID, 0), Waived, Created`
+
+func scanFee(row rowScanner) (*Fee, error) {
+	f := &Fee{}
+	var period, waived sql.NullTime
+	err := row.Scan(
+		&f.ID,
+		&f.AccountNumber,
+		&f.Operation,
+		&f.RuleID,
+		&f.Basis.Amount,
+		&f.Amount.Amount,
+		&f.Amount.Currency,
+		&f.TransferID,
+		&period,
+		&f.Status,
+		&f.TransactionID,
+		&f.WaivedBy,
+		&f.WaiverReason,
+		&f.WaiverTransactionID,
+		&waived,
+		&f.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	f.Basis.Currency = f.Amount.Currency
+	if period.Valid {
+		f.Period = &period.Time
+	}
+	if waived.Valid {
+		f.Waived = &waived.Time
+	}
+	return f, nil
+}
+
+// chargeFee calculates the fee with the schedule of the account type, books it as a posting of its
+// own and records it. It returns nil, recording nothing, if no tier of the schedule covers it.
+func (s *PostgresqlStore) chargeFee(tx *sql.Tx, fee *Fee, accountType AccountType, reference string) (*Fee, error) {
+	rule, err := getFeeRule(tx, accountType, fee.Operation, fee.Basis)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+	if err := fee.Apply(rule); err != nil {
+		return nil, err
+	}
+
+	if fee.Amount.IsPositive() {
+		feeIncome, err := s.getSystemAccount(tx, FeeIncomeSystemAccount, fee.Amount.Currency)
+		if err != nil {
+			return nil, err
+		}
+		t := fee.Transaction(feeIncome.AccountNumber, reference)
+		if err := s.postTransaction(tx, t); err != nil {
+			return nil, err
+		}
+		fee.TransactionID = t.ID
+	}
+	return fee, insertFee(tx, fee)
+}
+
+func insertFee(tx *sql.Tx, fee *Fee) error {
+	return tx.QueryRow(
+		`INSERT INTO Fee (AccountNumber, Operation, RuleID, Basis, Amount, Currency, TransferID, Period, Status, TransactionID, Created)
+         VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, NULLIF($7, 0), $8, $9, NULLIF($10, 0), $11)
+         RETURNING ID`,
+		fee.AccountNumber,
+		fee.Operation,
+		fee.RuleID,
+		fee.Basis.Amount,
+		fee.Amount.Amount,
+		fee.Amount.Currency,
+		fee.TransferID,
+		fee.Period,
+		fee.Status,
+		fee.TransactionID,
+		fee.Created,
+	).Scan(&fee.ID)
+}
+
+// chargeTransferFees charges the transfer fee and, for cross-currency transfers, the FX fee to the
+// source account. Both are calculated on the amount sent.
+func (s *PostgresqlStore) chargeTransferFees(tx *sql.Tx, transfer *Transfer) error {
+	var accountType AccountType
+	if err := tx.QueryRow(`SELECT Type FROM Account WHERE AccountNumber = $1`, transfer.FromAccount).Scan(&accountType); err != nil {
+		return err
+	}
+	operations := []FeeOperation{TransferFee}
+	if transfer.ConvertedAmount.Currency != transfer.Amount.Currency {
+		operations = append(operations, FxFee)
+	}
+	reference := "Transfer " + strconv.FormatInt(transfer.ID, 10)
+	for _, operation := range operations {
+		fee := NewFee(transfer.FromAccount, operation, transfer.Amount)
+		fee.TransferID = transfer.ID
+		charged, err := s.chargeFee(tx, fee, accountType, reference)
+		if err != nil {
+			return err
+		}
+		if charged != nil && charged.Amount.IsPositive() {
+			transfer.Fees = append(transfer.Fees, charged)
+		}
+	}
+	return nil
+}
+
+// GetMaintenanceFeeAccounts lists up to limit accounts numbered after the given one that owe the
+// maintenance fee for the month starting at period: active accounts that were open during it, have
+// a maintenance tier for their type and currency and were not charged for it yet.
+func (s *PostgresqlStore) GetMaintenanceFeeAccounts(period time.Time, after string, limit int) ([]string, error) {
+	rows, err := s.db.Query(
+		`SELECT a.AccountNumber FROM Account a
+         WHERE a.Type IN ($1, $2) AND a.Status = $3 AND a.Created < $4 AND a.AccountNumber > $7
+           AND EXISTS (SELECT 1 FROM FeeRule r
+                       WHERE r.AccountType = a.Type AND r.Operation = $5 AND r.Currency = a.Currency AND r.Deleted IS NULL)
+           AND NOT EXISTS (SELECT 1 FROM Fee f WHERE f.AccountNumber = a.AccountNumber AND f.Operation = $5 AND f.Period = $6)
+         ORDER BY a.AccountNumber
+         LIMIT $8`,
+		CurrentAccount,
+		SavingsAccount,
+		ActiveAccount,
+		period.AddDate(0, 1, 0),
+		MaintenanceFee,
+		period,
+		after,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var numbers []string
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, rows.Err()
+}
+
+// ChargeMaintenanceFee charges the maintenance fee for the month starting at period to the account.
+// The balance at charging time picks the tier. A month without a fee is recorded with a zero amount
+// so the account is not looked at again. It returns nil when the account no longer owes the fee,
+// e.g. because it was charged elsewhere in the meantime.
+func (s *PostgresqlStore) ChargeMaintenanceFee(accountNumber string, period time.Time) (*Fee, error) {
+	var fee *Fee
+	err := s.withTx(func(tx *sql.Tx) error {
+		locked, err := lockAccounts(tx, []string{accountNumber})
+		if err != nil {
+			return err
+		}
+		account := locked[accountNumber]
+		if account == nil || account.Status != ActiveAccount {
+			return nil
+		}
+		var paid bool
+		err = tx.QueryRow(
+			`SELECT EXISTS (SELECT 1 FROM Fee WHERE AccountNumber = $1 AND Operation = $2 AND Period = $3)`,
+			accountNumber,
+			MaintenanceFee,
+			period,
+		).Scan(&paid)
+		if err != nil || paid {
+			return err
+		}
+
+		basis := account.Balance
+		if basis.IsNegative() {
+			basis = Zero(basis.Currency)
+		}
+		fee = NewFee(accountNumber, MaintenanceFee, basis)
+		fee.Period = &period
+		charged, err := s.chargeFee(tx, fee, account.Type, "Maintenance "+period.Format("2006-01"))
+		if err != nil {
+			return err
+		}
+		// Balances below every tier are free.
+		if charged == nil {
+			return insertFee(tx, fee)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fee, nil
+}
+
+// GetFee returns the fee or nil.
+func (s *PostgresqlStore) GetFee(id int64) (*Fee, error) {
+	f, err := scanFee(s.db.QueryRow(`SELECT `+feeColumns+` FROM Fee WHERE ID = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// GetFees lists the fees charged to an account in [from, to), newest first. Operations that
+// turned out free are left out.
+func (s *PostgresqlStore) GetFees(accountNumber string, from time.Time, to time.Time) ([]*Fee, error) {
+	rows, err := s.db.Query(
+		`SELECT `+feeColumns+` FROM Fee
+         WHERE AccountNumber = $1 AND Amount > 0 AND Created >= $2 AND Created < $3
+         ORDER BY Created DESC, ID DESC`,
+		accountNumber,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fees []*Fee
+	for rows.Next() {
+		f, err := scanFee(rows)
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, f)
+	}
+	return fees, rows.Err()
+}
+
+// WaiveFee refunds a fee to its account by reversing its posting. The refund shows up in statements
+// as the reversal of the fee line.
+func (s *PostgresqlStore) WaiveFee(id int64, reason string, actorID string) (*Fee, error) {
+	var fee *Fee
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		fee, err = scanFee(tx.QueryRow(`SELECT `+feeColumns+` FROM Fee WHERE ID = $1 FOR UPDATE`, id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrFeeNotFound, "%d", id)
+			}
+			return err
+		}
+		if err := fee.CheckWaivable(); err != nil {
+			return err
+		}
+
+		var refundID int64
+		if fee.TransactionID != 0 {
+			refund, err := s.reverseTransaction(tx, fee.TransactionID, nil, "Fee waived: "+reason)
+			if err != nil {
+				return err
+			}
+			refundID = refund.ID
+		}
+		fee.Waive(actorID, reason, time.Now(), refundID)
+
+		_, err = tx.Exec(
+			`UPDATE Fee SET Status = $1, WaivedBy = $2, WaiverReason = $3, WaiverTransactionID = NULLIF($4, 0), Waived = $5
+             WHERE ID = $6`,
+			fee.Status,
+			fee.WaivedBy,
+			fee.WaiverReason,
+			fee.WaiverTransactionID,
+			fee.Waived,
+			fee.ID,
+		)
+		if err != nil {
+			return err
+		}
+		details := fmt.Sprintf("fee %d of %s: %s", fee.ID, fee.Amount.Format(), reason)
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "waive_fee", "account", fee.AccountNumber, details))
+	})
+	return fee, err
+}
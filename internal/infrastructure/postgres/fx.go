@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+const exchangeRateColumns = `ID, Base, Quote, Rate, ValidFrom, Created`
+
+func scanExchangeRate(row rowScanner) (*ExchangeRate, error) {
+	rate := &ExchangeRate{}
+	err := row.Scan(
+		&rate.ID,
+		&rate.Base,
+		&rate.Quote,
+		&rate.Rate,
+		&rate.ValidFrom,
+		&rate.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *PostgresqlStore) SaveExchangeRates(rates []*ExchangeRate) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		for _, rate := range rates {
+			err := tx.QueryRow(
+				`INSERT INTO ExchangeRate (Base, Quote, Rate, ValidFrom, Created)
+                 VALUES ($1, $2, $3, $4, $5)
+                 ON CONFLICT (Base, Quote, ValidFrom) DO UPDATE SET Rate = EXCLUDED.Rate, Created = EXCLUDED.Created
+                 RETURNING ID`,
+				rate.Base,
+				rate.Quote,
+				rate.Rate,
+				rate.ValidFrom,
+				rate.Created,
+			).Scan(&rate.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *PostgresqlStore) GetExchangeRates() ([]*ExchangeRate, error) {
+	rows, err := s.db.Query(`SELECT ` + exchangeRateColumns + ` FROM ExchangeRate ORDER BY Base, Quote, ValidFrom DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []*ExchangeRate
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (s *PostgresqlStore) GetExchangeRate(base Currency, quote Currency, at time.Time) (*ExchangeRate, error) {
+	return getExchangeRate(s.db, base, quote, at)
+}
+
+// getExchangeRate returns the newest rate valid at the given time. A rate quoted the other way
+// round is inverted when it is more recent than any direct quote.
+func getExchangeRate(q querier, base Currency, quote Currency, at time.Time) (*ExchangeRate, error) {
+	rate, err := scanExchangeRate(q.QueryRow(
+		`SELECT `+exchangeRateColumns+` FROM ExchangeRate
+         WHERE ((Base = $1 AND Quote = $2) OR (Base = $2 AND Quote = $1)) AND ValidFrom <= $3
+         ORDER BY ValidFrom DESC, (Base = $1) DESC, ID DESC
+         LIMIT 1`,
+		base,
+		quote,
+		at,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(ErrNoExchangeRate, "%s/%s at %s", base, quote, at.Format(time.RFC3339))
+		}
+		return nil, err
+	}
+	if rate.Base != base {
+		return rate.Inverse(), nil
+	}
+	return rate, nil
+}
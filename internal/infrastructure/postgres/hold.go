@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+const holdColumns = `ID, AccountNumber, Amount, Captured, Currency, Reference, Status, InitiatedBy, Expires, Created,
+                     COALESCE(LimitUserID, ''), LimitUsed, COALESCE(LimitCurrency, Currency)`
+
+func scanHold(row rowScanner) (*Hold, error) {
+	hold := &Hold{}
+	err := row.Scan(
+		&hold.ID,
+		&hold.AccountNumber,
+		&hold.Amount.Amount,
+		&hold.Captured.Amount,
+		&hold.Amount.Currency,
+		&hold.Reference,
+		&hold.Status,
+		&hold.InitiatedBy,
+		&hold.Expires,
+		&hold.Created,
+		&hold.LimitUserID,
+		&hold.LimitUsed.Amount,
+		&hold.LimitUsed.Currency,
+	)
+	if err != nil {
+		return nil, err
+	}
+	hold.Captured.Currency = hold.Amount.Currency
+	return hold, nil
+}
+
+// CreateHold reserves funds on the account. The hold is only placed if the available balance covers it.
+// Only accounts customers can pay from directly take holds.
+func (s *PostgresqlStore) CreateHold(hold *Hold) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		accounts, err := lockAccounts(tx, []string{hold.AccountNumber})
+		if err != nil {
+			return err
+		}
+		account, ok := accounts[hold.AccountNumber]
+		if !ok || !account.Payable() {
+			return errors.Wrapf(ErrAccountNotFound, "%s", hold.AccountNumber)
+		}
+		if err := account.CheckCanPost(); err != nil {
+			return err
+		}
+		available, err := account.AvailableBalance()
+		if err != nil {
+			return err
+		}
+		if available, err = available.Sub(hold.Amount); err != nil {
+			return err
+		}
+		if err := account.CheckBalance(available); err != nil {
+			return err
+		}
+		// The authorization is what uses up the initiator's limits; capturing it later doesn't again.
+		limitUserID := hold.InitiatedBy
+		if limitUserID == "" {
+			limitUserID = account.OwnerID
+		}
+		if hold.LimitUsed, err = s.useLimits(tx, limitUserID, hold.Created, hold.Amount); err != nil {
+			return err
+		}
+		if hold.LimitUsed.IsPositive() {
+			hold.LimitUserID = limitUserID
+		}
+
+		err = tx.QueryRow(
+			`INSERT INTO Hold (AccountNumber, Amount, Captured, Currency, Reference, Status, InitiatedBy, Expires, Created,
+                               LimitUserID, LimitUsed, LimitCurrency)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12)
+             RETURNING ID`,
+			hold.AccountNumber,
+			hold.Amount.Amount,
+			hold.Captured.Amount,
+			hold.Amount.Currency,
+			hold.Reference,
+			hold.Status,
+			hold.InitiatedBy,
+			hold.Expires,
+			hold.Created,
+			hold.LimitUserID,
+			hold.LimitUsed.Amount,
+			hold.LimitUsed.Currency,
+		).Scan(&hold.ID)
+		if err != nil {
+			return err
+		}
+		return adjustHeld(tx, hold.AccountNumber, hold.Amount)
+	})
+}
+
+// adjustHeld changes the amount the account has reserved by holds.
+func adjustHeld(tx *sql.Tx, accountNumber string, delta Money) error {
+	_, err := tx.Exec(`UPDATE Account SET Held = Held + $1 WHERE AccountNumber = $2`, delta.Amount, accountNumber)
+	return err
+}
+
+func (s *PostgresqlStore) GetHold(id int64) (*Hold, error) {
+	hold, err := scanHold(s.db.QueryRow(`SELECT `+holdColumns+` FROM Hold WHERE ID = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hold, nil
+}
+
+// GetHolds lists the holds of an account, newest first, optionally limited to the given statuses.
+func (s *PostgresqlStore) GetHolds(accountNumber string, statuses ...HoldStatus) ([]*Hold, error) {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	rows, err := s.db.Query(
+		`SELECT `+holdColumns+` FROM Hold
+         WHERE AccountNumber = $1 AND (cardinality($2::text[]) = 0 OR Status = ANY($2))
+         ORDER BY Created DESC, ID DESC`,
+		accountNumber,
+		pq.Array(names),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []*Hold
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, rows.Err()
+}
+
+func lockHold(tx *sql.Tx, id int64) (*Hold, error) {
+	hold, err := scanHold(tx.QueryRow(`SELECT `+holdColumns+` FROM Hold WHERE ID = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(ErrHoldNotFound, "%d", id)
+		}
+		return nil, err
+	}
+	return hold, nil
+}
+
+func updateHold(tx *sql.Tx, hold *Hold) error {
+	_, err := tx.Exec(`UPDATE Hold SET Captured = $1, Status = $2 WHERE ID = $3`, hold.Captured.Amount, hold.Status, hold.ID)
+	return err
+}
+
+// CaptureHold settles part or all of what the hold still reserves, posting it from the account to
+// the clearing account. Holds past their expiry can't be captured, whether or not they were
+// expired yet.
+func (s *PostgresqlStore) CaptureHold(id int64, amount Money) (*Hold, *Transaction, error) {
+	var hold *Hold
+	var t *Transaction
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		if hold, err = lockHold(tx, id); err != nil {
+			return err
+		}
+		clearing, err := s.getSystemAccount(tx, ClearingSystemAccount, hold.Amount.Currency)
+		if err != nil {
+			return err
+		}
+		if _, err := lockAccounts(tx, []string{hold.AccountNumber, clearing.AccountNumber}); err != nil {
+			return err
+		}
+		if err := hold.Capture(amount, time.Now()); err != nil {
+			return err
+		}
+
+		// The captured amount stops being reserved in the same step it is taken off the balance.
+		if err := adjustHeld(tx, hold.AccountNumber, amount.Neg()); err != nil {
+			return err
+		}
+		t = NewHoldCapture(hold, clearing.AccountNumber, amount)
+		if err := s.postTransaction(tx, t); err != nil {
+			return err
+		}
+		return updateHold(tx, hold)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return hold, t, nil
+}
+
+// ReleaseHold ends the hold and frees the funds it still reserves.
+func (s *PostgresqlStore) ReleaseHold(id int64) (*Hold, error) {
+	var hold *Hold
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		if hold, err = lockHold(tx, id); err != nil {
+			return err
+		}
+		return endHold(tx, hold, hold.Release)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// endHold frees what the hold still reserves and gives the limit usage of that part back.
+func endHold(tx *sql.Tx, hold *Hold, end func() error) error {
+	remaining := hold.Remaining()
+	if err := end(); err != nil {
+		return err
+	}
+	if err := adjustHeld(tx, hold.AccountNumber, remaining.Neg()); err != nil {
+		return err
+	}
+	unused, err := hold.UnusedLimit(remaining)
+	if err != nil {
+		return err
+	}
+	if unused.IsPositive() {
+		if err := releaseLimits(tx, hold.LimitUserID, hold.Created, unused); err != nil {
+			return err
+		}
+	}
+	return updateHold(tx, hold)
+}
+
+// ExpireHolds ends up to limit active holds that expired before now and returns how many it ended.
+// Holds locked by a concurrent capture or release are left for the next run.
+func (s *PostgresqlStore) ExpireHolds(now time.Time, limit int) (int, error) {
+	expired := 0
+	err := s.withTx(func(tx *sql.Tx) error {
+		rows, err := tx.Query(
+			`SELECT `+holdColumns+` FROM Hold
+             WHERE Status = $1 AND Expires <= $2
+             ORDER BY Expires, ID
+             LIMIT $3
+             FOR UPDATE SKIP LOCKED`,
+			ActiveHold,
+			now,
+			limit,
+		)
+		if err != nil {
+			return err
+		}
+		var holds []*Hold
+		for rows.Next() {
+			hold, err := scanHold(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			holds = append(holds, hold)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// Lock the affected accounts in the usual order before touching them.
+		numbers := make([]string, len(holds))
+		for i, hold := range holds {
+			numbers[i] = hold.AccountNumber
+		}
+		if _, err := lockAccounts(tx, numbers); err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			if err := endHold(tx, hold, hold.Expire); err != nil {
+				return err
+			}
+		}
+		expired = len(holds)
+		return nil
+	})
+	return expired, err
+}
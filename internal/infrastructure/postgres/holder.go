@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+)
+
+const holderColumns = `AccountNumber, UserID, Role, AddedBy, Created`
+
+func scanAccountHolder(row rowScanner) (*AccountHolder, error) {
+	holder := &AccountHolder{}
+	err := row.Scan(&holder.AccountNumber, &holder.UserID, &holder.Role, &holder.AddedBy, &holder.Created)
+	if err != nil {
+		return nil, err
+	}
+	return holder, nil
+}
+
+func insertAccountHolder(q querier, holder *AccountHolder) error {
+	_, err := q.Exec(
+		`INSERT INTO AccountHolder (`+holderColumns+`) VALUES ($1, $2, $3, $4, $5)
+         ON CONFLICT (AccountNumber, UserID) DO UPDATE SET Role = EXCLUDED.Role`,
+		holder.AccountNumber,
+		holder.UserID,
+		holder.Role,
+		holder.AddedBy,
+		holder.Created,
+	)
+	return err
+}
+
+// GetAccountHolder returns how the user holds the account, or nil if they don't.
+func (s *PostgresqlStore) GetAccountHolder(accountNumber string, userID string) (*AccountHolder, error) {
+	return getAccountHolder(s.db, accountNumber, userID)
+}
+
+func getAccountHolder(q querier, accountNumber string, userID string) (*AccountHolder, error) {
+	holder, err := scanAccountHolder(q.QueryRow(
+		`SELECT `+holderColumns+` FROM AccountHolder WHERE AccountNumber = $1 AND UserID = $2`,
+		accountNumber,
+		userID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return holder, nil
+}
+
+// GetAccountHolders lists the holders of an account, owners first.
+func (s *PostgresqlStore) GetAccountHolders(accountNumber string) ([]*AccountHolder, error) {
+	rows, err := s.db.Query(
+		`SELECT `+holderColumns+` FROM AccountHolder
+         WHERE AccountNumber = $1
+         ORDER BY Role = 'owner' DESC, Created, UserID`,
+		accountNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holders []*AccountHolder
+	for rows.Next() {
+		holder, err := scanAccountHolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, holder)
+	}
+	return holders, rows.Err()
+}
+
+// checkCanMoveMoney fails with ErrHolderNotAllowed unless the user may pay out of the account.
+func checkCanMoveMoney(q querier, accountNumber string, userID string) error {
+	holder, err := getAccountHolder(q, accountNumber, userID)
+	if err != nil {
+		return err
+	}
+	if holder == nil || !holder.CanMoveMoney() {
+		return errors.Wrapf(ErrHolderNotAllowed, "account %s", accountNumber)
+	}
+	return nil
+}
+
+// SaveAccountHolder adds a holder to an account or changes the role of an existing one.
+func (s *PostgresqlStore) SaveAccountHolder(holder *AccountHolder, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		account, err := lockCustomerAccount(tx, holder.AccountNumber)
+		if err != nil {
+			return err
+		}
+		if account.Status == ClosedAccount {
+			return errors.Wrapf(ErrAccountNotActive, "account %s is %s", account.AccountNumber, account.Status)
+		}
+		previous, err := getAccountHolder(tx, holder.AccountNumber, holder.UserID)
+		if err != nil {
+			return err
+		}
+		if previous != nil && previous.Role == OwnerRole && holder.Role != OwnerRole {
+			if err := checkOtherOwner(tx, holder.AccountNumber, holder.UserID); err != nil {
+				return err
+			}
+		}
+		if err := insertAccountHolder(tx, holder); err != nil {
+			return err
+		}
+
+		action, details := "add_holder", holder.UserID+" as "+string(holder.Role)
+		if previous != nil {
+			action, details = "change_holder", holder.UserID+": "+string(previous.Role)+" -> "+string(holder.Role)
+			holder.AddedBy, holder.Created = previous.AddedBy, previous.Created
+		}
+		return recordAuditEvent(tx, NewAuditEvent(actorID, action, "account", holder.AccountNumber, details))
+	})
+}
+
+// RemoveAccountHolder takes the user's access to the account away. The last owner can't be removed.
+func (s *PostgresqlStore) RemoveAccountHolder(accountNumber string, userID string, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return removeAccountHolder(tx, accountNumber, userID, actorID)
+	})
+}
+
+func removeAccountHolder(tx *sql.Tx, accountNumber string, userID string, actorID string) error {
+	account, err := lockCustomerAccount(tx, accountNumber)
+	if err != nil {
+		return err
+	}
+	holder, err := getAccountHolder(tx, accountNumber, userID)
+	if err != nil {
+		return err
+	}
+	if holder == nil {
+		return errors.Wrapf(ErrHolderNotFound, "%s on %s", userID, accountNumber)
+	}
+	if holder.Role == OwnerRole {
+		if err := checkOtherOwner(tx, accountNumber, userID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM AccountHolder WHERE AccountNumber = $1 AND UserID = $2`, accountNumber, userID); err != nil {
+		return err
+	}
+	// The longest-standing remaining owner becomes the primary owner.
+	if account.OwnerID == userID {
+		_, err := tx.Exec(
+			`UPDATE Account SET OwnerID = (
+                 SELECT UserID FROM AccountHolder WHERE AccountNumber = $1 AND Role = 'owner' ORDER BY Created, UserID LIMIT 1
+             ) WHERE AccountNumber = $1`,
+			accountNumber,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return recordAuditEvent(tx, NewAuditEvent(actorID, "remove_holder", "account", accountNumber, userID))
+}
+
+// checkOtherOwner fails with ErrLastOwner unless someone besides userID owns the account.
+func checkOtherOwner(tx *sql.Tx, accountNumber string, userID string) error {
+	var others int
+	err := tx.QueryRow(
+		`SELECT count(*) FROM AccountHolder WHERE AccountNumber = $1 AND Role = 'owner' AND UserID <> $2`,
+		accountNumber,
+		userID,
+	).Scan(&others)
+	if err != nil {
+		return err
+	}
+	if others == 0 {
+		return errors.Wrapf(ErrLastOwner, "account %s", accountNumber)
+	}
+	return nil
+}
+
+func lockCustomerAccount(tx *sql.Tx, accountNumber string) (*Account, error) {
+	locked, err := lockAccounts(tx, []string{accountNumber})
+	if err != nil {
+		return nil, err
+	}
+	account, ok := locked[accountNumber]
+	if !ok || account.Type == InternalAccount {
+		return nil, errors.Wrapf(ErrAccountNotFound, "%s", accountNumber)
+	}
+	return account, nil
+}
+
+// ownerHolder is the holder every new customer account starts out with.
+func ownerHolder(account *Account) *AccountHolder {
+	holder := NewAccountHolder(account.AccountNumber, account.OwnerID, OwnerRole, account.OwnerID)
+	holder.Created = account.Created
+	return holder
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+// ClaimIdempotencyKey stores record as an in-flight request unless the caller already used the key.
+// It returns nil when the claim succeeded, or the record previously stored under the key.
+func (s *PostgresqlStore) ClaimIdempotencyKey(record *IdempotencyRecord) (*IdempotencyRecord, error) {
+	var existing *IdempotencyRecord
+	err := s.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`DELETE FROM IdempotencyKey WHERE Key = $1 AND CallerID = $2 AND Expires <= $3`,
+			record.Key,
+			record.CallerID,
+			record.Created,
+		)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(
+			`INSERT INTO IdempotencyKey (Key, CallerID, Fingerprint, Completed, StatusCode, Body, Created, Expires)
+             VALUES ($1, $2, $3, false, 0, '', $4, $5)
+             ON CONFLICT (Key, CallerID) DO NOTHING`,
+			record.Key,
+			record.CallerID,
+			record.Fingerprint,
+			record.Created,
+			record.Expires,
+		)
+		if err != nil {
+			return err
+		}
+		if inserted, err := res.RowsAffected(); err != nil || inserted == 1 {
+			return err
+		}
+
+		existing = &IdempotencyRecord{}
+		return tx.QueryRow(
+			`SELECT Key, CallerID, Fingerprint, Completed, StatusCode, Body, Created, Expires
+             FROM IdempotencyKey WHERE Key = $1 AND CallerID = $2`,
+			record.Key,
+			record.CallerID,
+		).Scan(
+			&existing.Key,
+			&existing.CallerID,
+			&existing.Fingerprint,
+			&existing.Completed,
+			&existing.StatusCode,
+			&existing.Body,
+			&existing.Created,
+			&existing.Expires,
+		)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "claiming idempotency key")
+	}
+	return existing, nil
+}
+
+// CompleteIdempotencyKey stores the response produced for a claimed key.
+func (s *PostgresqlStore) CompleteIdempotencyKey(record *IdempotencyRecord) error {
+	_, err := s.db.Exec(
+		`UPDATE IdempotencyKey SET Completed = true, StatusCode = $1, Body = $2 WHERE Key = $3 AND CallerID = $4`,
+		record.StatusCode,
+		record.Body,
+		record.Key,
+		record.CallerID,
+	)
+	return err
+}
+
+// ReleaseIdempotencyKey forgets a claimed key so the request can be retried.
+func (s *PostgresqlStore) ReleaseIdempotencyKey(key string, callerID string) error {
+	_, err := s.db.Exec(`DELETE FROM IdempotencyKey WHERE Key = $1 AND CallerID = $2`, key, callerID)
+	return err
+}
+
+// PurgeIdempotencyKeys deletes up to limit keys that expired before now and returns how many it deleted.
+func (s *PostgresqlStore) PurgeIdempotencyKeys(now time.Time, limit int) (int, error) {
+	res, err := s.db.Exec(
+		`DELETE FROM IdempotencyKey
+         WHERE (Key, CallerID) IN (SELECT Key, CallerID FROM IdempotencyKey WHERE Expires <= $1 LIMIT $2)`,
+		now,
+		limit,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "purging idempotency keys")
+	}
+	deleted, err := res.RowsAffected()
+	return int(deleted), err
+}
@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+// SaveInterestRate sets the annual rate of an account from rate.ValidFrom onwards. A rate saved
+// again for the same start replaces the earlier one.
+func (s *PostgresqlStore) SaveInterestRate(rate *InterestRate, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`INSERT INTO InterestRate (AccountNumber, AnnualRate, ValidFrom, Created)
+             VALUES ($1, $2, $3, $4)
+             ON CONFLICT (AccountNumber, ValidFrom) DO UPDATE SET AnnualRate = EXCLUDED.AnnualRate, Created = EXCLUDED.Created
+             RETURNING ID`,
+			rate.AccountNumber,
+			rate.AnnualRate,
+			rate.ValidFrom,
+			rate.Created,
+		).Scan(&rate.ID)
+		if err != nil {
+			return err
+		}
+		details := rate.AnnualRate + " from " + rate.ValidFrom.Format(time.RFC3339)
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "set_interest_rate", "account", rate.AccountNumber, details))
+	})
+}
+
+// GetInterestRate returns the rate of the account valid at the given time, or nil if it has none.
+func (s *PostgresqlStore) GetInterestRate(accountNumber string, at time.Time) (*InterestRate, error) {
+	rate := &InterestRate{}
+	err := s.db.QueryRow(
+		`SELECT ID, AccountNumber, AnnualRate, ValidFrom, Created
+         FROM InterestRate
+         WHERE AccountNumber = $1 AND ValidFrom <= $2
+         ORDER BY ValidFrom DESC
+         LIMIT 1`,
+		accountNumber,
+		at,
+	).Scan(&rate.ID, &rate.AccountNumber, &rate.AnnualRate, &rate.ValidFrom, &rate.Created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rate, nil
+}
+
+const accrualColumns = `AccountNumber, Day, Balance, Currency, AnnualRate, DayCount, Amount, COALESCE(TransactionID, 0), Created`
+
+func scanInterestAccrual(row rowScanner) (*InterestAccrual, error) {
+	accrual := &InterestAccrual{}
+	err := row.Scan(
+		&accrual.AccountNumber,
+		&accrual.Day,
+		&accrual.Balance.Amount,
+		&accrual.Balance.Currency,
+		&accrual.AnnualRate,
+		&accrual.DayCount,
+		&accrual.Amount,
+		&accrual.TransactionID,
+		&accrual.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return accrual, nil
+}
+
+// SaveInterestAccrual stores the accrual of a day unless that day was accrued before, in which case
+// it reports false and leaves the stored accrual untouched.
+func (s *PostgresqlStore) SaveInterestAccrual(accrual *InterestAccrual) (bool, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO InterestAccrual (AccountNumber, Day, Balance, Currency, AnnualRate, DayCount, Amount, Created)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+         ON CONFLICT (AccountNumber, Day) DO NOTHING`,
+		accrual.AccountNumber,
+		accrual.Day,
+		accrual.Balance.Amount,
+		accrual.Balance.Currency,
+		accrual.AnnualRate,
+		accrual.DayCount,
+		accrual.Amount,
+		accrual.Created,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// GetLastInterestAccrual returns the most recent accrual of an account, or nil if it has none.
+func (s *PostgresqlStore) GetLastInterestAccrual(accountNumber string) (*InterestAccrual, error) {
+	accrual, err := scanInterestAccrual(s.db.QueryRow(
+		`SELECT `+accrualColumns+` FROM InterestAccrual WHERE AccountNumber = $1 ORDER BY Day DESC LIMIT 1`,
+		accountNumber,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return accrual, nil
+}
+
+// GetInterestAccruals lists the accruals of an account for the days in [from, to).
+func (s *PostgresqlStore) GetInterestAccruals(accountNumber string, from time.Time, to time.Time) ([]*InterestAccrual, error) {
+	rows, err := s.db.Query(
+		`SELECT `+accrualColumns+` FROM InterestAccrual
+         WHERE AccountNumber = $1 AND Day >= $2 AND Day < $3
+         ORDER BY Day`,
+		accountNumber,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanInterestAccruals(rows)
+}
+
+func scanInterestAccruals(rows *sql.Rows) ([]*InterestAccrual, error) {
+	var accruals []*InterestAccrual
+	for rows.Next() {
+		accrual, err := scanInterestAccrual(rows)
+		if err != nil {
+			return nil, err
+		}
+		accruals = append(accruals, accrual)
+	}
+	return accruals, rows.Err()
+}
+
+// CapitaliseInterest posts the interest accrued on the account for the days before the given one
+// and marks those accruals as paid. It returns nil if there was nothing to pay out; running it
+// again for the same period does nothing.
+func (s *PostgresqlStore) CapitaliseInterest(accountNumber string, before time.Time) (*Transaction, error) {
+	var t *Transaction
+	err := s.withTx(func(tx *sql.Tx) error {
+		// Locking the pending accruals keeps concurrent runs from paying them twice.
+		rows, err := tx.Query(
+			`SELECT `+accrualColumns+` FROM InterestAccrual
+             WHERE AccountNumber = $1 AND Day < $2 AND Capitalised IS NULL
+             ORDER BY Day
+             FOR UPDATE`,
+			accountNumber,
+			before,
+		)
+		if err != nil {
+			return err
+		}
+		accruals, err := scanInterestAccruals(rows)
+		rows.Close()
+		if err != nil || len(accruals) == 0 {
+			return err
+		}
+
+		currency := accruals[0].Balance.Currency
+		amount, err := SumAccruals(accruals, currency)
+		if err != nil {
+			return err
+		}
+
+		// Credit and overdraft interest of the same period are netted into a single posting.
+		var transactionID sql.NullInt64
+		from, to := accruals[0].Day, accruals[len(accruals)-1].Day.AddDate(0, 0, 1)
+		switch {
+		case amount.IsPositive():
+			expense, err := s.getSystemAccount(tx, InterestExpenseSystemAccount, currency)
+			if err != nil {
+				return err
+			}
+			if t, err = NewInterestPayment(accountNumber, expense.AccountNumber, amount, from, to); err != nil {
+				return err
+			}
+		case amount.IsNegative():
+			income, err := s.getSystemAccount(tx, InterestIncomeSystemAccount, currency)
+			if err != nil {
+				return err
+			}
+			if t, err = NewInterestCharge(accountNumber, income.AccountNumber, amount.Neg(), from, to); err != nil {
+				return err
+			}
+		}
+		if t != nil {
+			if err := s.postTransaction(tx, t); err != nil {
+				return err
+			}
+			transactionID = sql.NullInt64{Int64: t.ID, Valid: true}
+		}
+
+		_, err = tx.Exec(
+			`UPDATE InterestAccrual SET TransactionID = $1, Capitalised = $2
+             WHERE AccountNumber = $3 AND Day < $4 AND Capitalised IS NULL`,
+			transactionID,
+			time.Now(),
+			accountNumber,
+			before,
+		)
+		return err
+	})
+	return t, err
+}
@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"sort"
+	"time"
+)
+
+// PostTransaction validates and books a transaction, keeping the balance of every affected account
+// in step with its ledger entries.
+func (s *PostgresqlStore) PostTransaction(t *Transaction) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.postTransaction(tx, t)
+	})
+}
+
+func (s *PostgresqlStore) postTransaction(tx *sql.Tx, t *Transaction) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	numbers := make([]string, 0, len(t.Entries))
+	for _, e := range t.Entries {
+		numbers = append(numbers, e.AccountNumber)
+	}
+	accounts, err := lockAccounts(tx, numbers)
+	if err != nil {
+		return err
+	}
+
+	opening := map[string]Money{}
+	for _, e := range t.Entries {
+		acc, ok := accounts[e.AccountNumber]
+		if !ok {
+			return errors.Wrapf(ErrAccountNotFound, "%s", e.AccountNumber)
+		}
+		if err := acc.CheckCanPost(); err != nil {
+			return err
+		}
+		if acc.Balance.Currency != e.Amount.Currency {
+			return errors.Wrapf(ErrCurrencyMismatch, "account %s is held in %s", acc.AccountNumber, acc.Balance.Currency)
+		}
+		if _, ok := opening[acc.AccountNumber]; !ok {
+			opening[acc.AccountNumber] = acc.Balance
+		}
+		if acc.Balance, err = acc.Balance.Add(e.SignedAmount()); err != nil {
+			return err
+		}
+	}
+
+	// Only accounts that lost money in this posting have to stay within their limits.
+	// Funds reserved by authorization holds are not available to other debits.
+	for number, acc := range accounts {
+		if t.EnforcesLimits() && acc.Balance.Amount < opening[number].Amount {
+			available, err := acc.AvailableBalance()
+			if err != nil {
+				return err
+			}
+			if err := acc.CheckBalance(available); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t.CountsTowardsLimits() {
+		if err := s.consumeLimits(tx, t, accounts); err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO LedgerTransaction (Type, Reference, Description, ExchangeRate, ReversalOf, Created)
+         VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6)
+         RETURNING ID`,
+		t.Type,
+		t.Reference,
+		t.Description,
+		t.ExchangeRate,
+		t.ReversalOf,
+		t.Created,
+	).Scan(&t.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range t.Entries {
+		e := &t.Entries[i]
+		e.TransactionID = t.ID
+		err = tx.QueryRow(
+			`INSERT INTO LedgerEntry (TransactionID, AccountNumber, Direction, Amount, Currency, Created)
+             VALUES ($1, $2, $3, $4, $5, $6)
+             RETURNING ID`,
+			e.TransactionID,
+			e.AccountNumber,
+			e.Direction,
+			e.Amount.Amount,
+			e.Amount.Currency,
+			e.Created,
+		).Scan(&e.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, acc := range accounts {
+		_, err = tx.Exec(`UPDATE Account SET Balance = $1 WHERE AccountNumber = $2`, acc.Balance.Amount, acc.AccountNumber)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockAccounts loads the given accounts with a row lock held until the end of the transaction.
+// Rows are always locked in account number order so concurrent postings cannot deadlock.
+func lockAccounts(tx *sql.Tx, numbers []string) (map[string]*Account, error) {
+	unique := make([]string, 0, len(numbers))
+	seen := map[string]bool{}
+	for _, n := range numbers {
+		if !seen[n] {
+			seen[n] = true
+			unique = append(unique, n)
+		}
+	}
+	sort.Strings(unique)
+
+	rows, err := tx.Query(
+		`SELECT `+accountColumns+` FROM Account WHERE AccountNumber = ANY($1) ORDER BY AccountNumber FOR UPDATE`,
+		pq.Array(unique),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := map[string]*Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts[account.AccountNumber] = account
+	}
+	return accounts, rows.Err()
+}
+
+func (s *PostgresqlStore) GetTransaction(id int64) (*Transaction, error) {
+	return getTransaction(s.db, id)
+}
+
+func getTransaction(q querier, id int64) (*Transaction, error) {
+	t := &Transaction{}
+	err := q.QueryRow(
+		`SELECT ID, Type, Reference, Description, ExchangeRate, COALESCE(ReversalOf, 0), Created
+         FROM LedgerTransaction WHERE ID = $1`,
+		id,
+	).Scan(&t.ID, &t.Type, &t.Reference, &t.Description, &t.ExchangeRate, &t.ReversalOf, &t.Created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rows, err := q.Query(`SELECT `+entryColumns+` FROM LedgerEntry WHERE TransactionID = $1 ORDER BY ID`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanLedgerEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		t.Entries = append(t.Entries, *entry)
+	}
+	return t, rows.Err()
+}
+
+func (s *PostgresqlStore) GetLedgerEntries(accountNumber string) ([]*LedgerEntry, error) {
+	rows, err := s.db.Query(
+		`SELECT `+entryColumns+` FROM LedgerEntry WHERE AccountNumber = $1 ORDER BY Created, ID`,
+		accountNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*LedgerEntry
+	for rows.Next() {
+		entry, err := scanLedgerEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+const entryColumns = `ID, TransactionID, AccountNumber, Direction, Amount, Currency, Created`
+
+func scanLedgerEntry(row rowScanner) (*LedgerEntry, error) {
+	entry := &LedgerEntry{}
+	err := row.Scan(
+		&entry.ID,
+		&entry.TransactionID,
+		&entry.AccountNumber,
+		&entry.Direction,
+		&entry.Amount.Amount,
+		&entry.Amount.Currency,
+		&entry.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *PostgresqlStore) GetSystemAccount(code SystemAccountCode, currency Currency) (*Account, error) {
+	var account *Account
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		account, err = s.getSystemAccount(tx, code, currency)
+		return err
+	})
+	return account, err
+}
+
+// getSystemAccount returns the internal account registered under code for the currency,
+// opening it on first use.
+func (s *PostgresqlStore) getSystemAccount(tx *sql.Tx, code SystemAccountCode, currency Currency) (*Account, error) {
+	query := `SELECT ` + prefixColumns("a.", accountColumns) + `
+              FROM SystemAccount s JOIN Account a ON a.AccountNumber = s.AccountNumber
+              WHERE s.Code = $1 AND s.Currency = $2`
+
+	account, err := scanAccount(tx.QueryRow(query, code, currency))
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// Serialise creation so two postings can't open the same system account twice.
+	if _, err = tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('SystemAccount:' || $1::text || ':' || $2::text))`, code, currency); err != nil {
+		return nil, err
+	}
+	account, err = scanAccount(tx.QueryRow(query, code, currency))
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	account = &Account{
+		Balance: Zero(currency),
+		Type:    InternalAccount,
+		Status:  ActiveAccount,
+		Created: time.Now(),
+	}
+	if err := s.insertAccount(tx, account); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO SystemAccount (Code, Currency, AccountNumber) VALUES ($1, $2, $3)`,
+		code,
+		currency,
+		account.AccountNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
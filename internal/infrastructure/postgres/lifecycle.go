@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"strings"
+)
+
+func statusStrings(statuses []AccountStatus) []string {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	return values
+}
+
+// UpdateAccountStatus moves the account to a new status if the transition is allowed.
+func (s *PostgresqlStore) UpdateAccountStatus(accountNumber string, status AccountStatus, reason string, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return updateAccountStatus(tx, accountNumber, status, reason, actorID)
+	})
+}
+
+func updateAccountStatus(tx *sql.Tx, accountNumber string, status AccountStatus, reason string, actorID string) error {
+	locked, err := lockAccounts(tx, []string{accountNumber})
+	if err != nil {
+		return err
+	}
+	account, ok := locked[accountNumber]
+	if !ok || account.Type == InternalAccount {
+		return errors.Wrapf(ErrAccountNotFound, "%s", accountNumber)
+	}
+	if err := account.CheckTransition(status); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE Account SET Status = $1 WHERE AccountNumber = $2`, status, accountNumber); err != nil {
+		return err
+	}
+	event := NewAuditEvent(actorID, "change_status", "account", accountNumber, fmt.Sprintf("%s -> %s: %s", account.Status, status, reason))
+	return recordAuditEvent(tx, event)
+}
+
+// CloseAccount closes the account. A remaining positive balance is first transferred to sweepTo,
+// which is returned as the sweep transfer; without a sweep target the balance must be zero.
+func (s *PostgresqlStore) CloseAccount(accountNumber string, sweepTo string, reason string, actorID string) (*Transfer, error) {
+	var sweep *Transfer
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		sweep, err = s.closeAccount(tx, accountNumber, sweepTo, reason, actorID)
+		return err
+	})
+	return sweep, err
+}
+
+func (s *PostgresqlStore) closeAccount(tx *sql.Tx, accountNumber string, sweepTo string, reason string, actorID string) (*Transfer, error) {
+	locked, err := lockAccounts(tx, []string{accountNumber})
+	if err != nil {
+		return nil, err
+	}
+	account, ok := locked[accountNumber]
+	if !ok || account.Type == InternalAccount {
+		return nil, errors.Wrapf(ErrAccountNotFound, "%s", accountNumber)
+	}
+	// Term deposit accounts are closed when their deposit is paid back.
+	if account.Type == TermDepositAccount {
+		return nil, errors.Wrapf(ErrTermDepositLocked, "%s", accountNumber)
+	}
+	// Active holds could still be captured against the closed account.
+	if !account.Held.IsZero() {
+		return nil, errors.Wrapf(ErrAccountHasHolds, "%s held", account.Held.Format())
+	}
+
+	var sweep *Transfer
+	if account.Balance.IsPositive() && sweepTo != "" {
+		sweep, err = NewClosureSweep(accountNumber, sweepTo, account.Balance, actorID)
+		if err != nil {
+			return nil, err
+		}
+		// Closing an account is free: the whole balance is swept.
+		if err := s.bookTransfer(tx, sweep); err != nil {
+			return nil, errors.Wrap(err, "sweeping balance")
+		}
+	}
+
+	return sweep, updateAccountStatus(tx, accountNumber, ClosedAccount, reason, actorID)
+}
+
+// DeactivateUser closes every account the user is the only owner of, removes them from the accounts
+// they share with others and disables the user. Accounts with money left on them prevent
+// deactivation; nothing is deleted so the history stays available.
+func (s *PostgresqlStore) DeactivateUser(userID string, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		rows, err := tx.Query(
+			`SELECT h.AccountNumber, h.Role = 'owner' AND NOT EXISTS (
+                 SELECT 1 FROM AccountHolder o WHERE o.AccountNumber = h.AccountNumber AND o.Role = 'owner' AND o.UserID <> h.UserID
+             )
+             FROM AccountHolder h JOIN Account a ON a.AccountNumber = h.AccountNumber
+             WHERE h.UserID = $1 AND a.Status <> $2
+             ORDER BY h.AccountNumber`,
+			userID,
+			ClosedAccount,
+		)
+		if err != nil {
+			return err
+		}
+		var open, shared []string
+		for rows.Next() {
+			var number string
+			var soleOwner bool
+			if err := rows.Scan(&number, &soleOwner); err != nil {
+				rows.Close()
+				return err
+			}
+			if soleOwner {
+				open = append(open, number)
+			} else {
+				shared = append(shared, number)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, number := range shared {
+			if err := removeAccountHolder(tx, number, userID, actorID); err != nil {
+				return err
+			}
+		}
+		var blocked []string
+		for _, number := range open {
+			if _, err := s.closeAccount(tx, number, "", "User deactivated", actorID); err != nil {
+				if errors.Is(err, ErrAccountNotEmpty) || errors.Is(err, ErrAccountHasHolds) || errors.Is(err, ErrInvalidStatusTransition) {
+					blocked = append(blocked, number)
+					continue
+				}
+				return err
+			}
+		}
+		if len(blocked) > 0 {
+			return errors.Wrapf(ErrOpenAccountsPreventClose, "%s", strings.Join(blocked, ", "))
+		}
+
+		res, err := tx.Exec(`UPDATE "User" SET Active = false WHERE ID = $1 AND Active`, userID)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errors.Wrapf(ErrUserDeactivated, "%s", userID)
+		}
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "deactivate", "user", userID, ""))
+	})
+}
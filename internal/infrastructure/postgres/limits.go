@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"sort"
+	"time"
+)
+
+// consumeLimits charges what the transaction takes out of customer accounts against the limits of
+// the user who initiated it, or of the accounts' owners if nobody did. A debit is not limited as far
+// as the transaction credits the money back to accounts of the same owner.
+func (s *PostgresqlStore) consumeLimits(tx *sql.Tx, t *Transaction, accounts map[string]*Account) error {
+	net := map[string]map[Currency]int64{}
+	for _, e := range t.Entries {
+		acc := accounts[e.AccountNumber]
+		if acc.Type == InternalAccount {
+			continue
+		}
+		if net[acc.OwnerID] == nil {
+			net[acc.OwnerID] = map[Currency]int64{}
+		}
+		net[acc.OwnerID][e.Amount.Currency] += e.SignedAmount().Amount
+	}
+
+	outgoing := map[string][]Money{}
+	for owner, balance := range net {
+		spent, err := ownerPayouts(t, balance)
+		if err != nil {
+			return err
+		}
+		if len(spent) == 0 {
+			continue
+		}
+		userID := t.InitiatedBy
+		if userID == "" {
+			userID = owner
+		}
+		outgoing[userID] = append(outgoing[userID], spent...)
+	}
+
+	users := make([]string, 0, len(outgoing))
+	for user := range outgoing {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	for _, user := range users {
+		if _, err := s.useLimits(tx, user, t.Created, outgoing[user]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ownerPayouts returns what the transaction takes out of one owner's accounts, given the net change
+// it makes to them per currency. In a cross-currency posting, what the owner receives in the other
+// currency offsets what they pay at the rate the transaction applied.
+func ownerPayouts(t *Transaction, balance map[Currency]int64) ([]Money, error) {
+	currencies := make([]string, 0, len(balance))
+	for currency := range balance {
+		currencies = append(currencies, string(currency))
+	}
+	sort.Strings(currencies)
+
+	var spent []Money
+	for _, base := range currencies {
+		paid := NewMoney(-balance[Currency(base)], Currency(base))
+		if !paid.IsPositive() {
+			continue
+		}
+		for _, quote := range currencies {
+			received := NewMoney(balance[Currency(quote)], Currency(quote))
+			if t.ExchangeRate == "" || !received.IsPositive() || !paid.IsPositive() {
+				continue
+			}
+			rate, err := NewExchangeRate(Currency(base), Currency(quote), t.ExchangeRate, t.Created)
+			if err != nil {
+				return nil, err
+			}
+			back, err := rate.Inverse().Convert(received)
+			if err != nil {
+				return nil, err
+			}
+			if back.Amount < paid.Amount {
+				paid.Amount -= back.Amount
+				balance[received.Currency] = 0
+				continue
+			}
+			used, err := rate.Convert(paid)
+			if err != nil {
+				return nil, err
+			}
+			paid.Amount = 0
+			if balance[received.Currency] -= used.Amount; balance[received.Currency] < 0 {
+				balance[received.Currency] = 0
+			}
+		}
+		if paid.IsPositive() {
+			spent = append(spent, paid)
+		}
+	}
+	return spent, nil
+}
+
+// useLimits records amounts paid out by the user at the given time, failing if they don't fit into
+// the user's daily or monthly limit. Concurrent payments of the same user are serialised, so two
+// of them can't both use the same headroom. Users without any limit are not tracked, so their
+// payments in other currencies don't need an exchange rate. It returns the total recorded, in the
+// currency limits are kept in.
+func (s *PostgresqlStore) useLimits(tx *sql.Tx, userID string, at time.Time, amounts ...Money) (Money, error) {
+	total := Zero(s.defaultLimits.Daily.Currency)
+	if err := lockLimits(tx, userID); err != nil {
+		return total, err
+	}
+	limits, err := s.getLimits(tx, userID, at)
+	if err != nil {
+		return total, err
+	}
+	if limits.Unlimited() {
+		return total, nil
+	}
+
+	for _, amount := range amounts {
+		converted, err := s.toLimitCurrency(tx, amount, at)
+		if err != nil {
+			return total, err
+		}
+		if total, err = total.Add(converted); err != nil {
+			return total, err
+		}
+	}
+	if err := limits.Daily.Check(total); err != nil {
+		return total, err
+	}
+	if err := limits.Monthly.Check(total); err != nil {
+		return total, err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO LimitUsage (UserID, Day, Currency, Amount) VALUES ($1, $2, $3, $4)
+         ON CONFLICT (UserID, Day, Currency) DO UPDATE SET Amount = LimitUsage.Amount + EXCLUDED.Amount`,
+		userID,
+		Day(at),
+		total.Currency,
+		total.Amount,
+	)
+	return total, err
+}
+
+// releaseLimits gives back limit usage recorded for the user on the day of at, for payments that
+// were authorized but never made.
+func releaseLimits(tx *sql.Tx, userID string, at time.Time, amount Money) error {
+	if err := lockLimits(tx, userID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		`UPDATE LimitUsage SET Amount = GREATEST(Amount - $1, 0) WHERE UserID = $2 AND Day = $3 AND Currency = $4`,
+		amount.Amount,
+		userID,
+		Day(at),
+		amount.Currency,
+	)
+	return err
+}
+
+// lockLimits serialises the changes to the limit usage of a user until the end of the transaction.
+func lockLimits(tx *sql.Tx, userID string) error {
+	_, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('Limits:' || $1::text))`, userID)
+	return err
+}
+
+// toLimitCurrency converts an amount to the currency limits are kept in, at the rate valid at the given time.
+func (s *PostgresqlStore) toLimitCurrency(q querier, amount Money, at time.Time) (Money, error) {
+	currency := s.defaultLimits.Daily.Currency
+	if amount.Currency == currency {
+		return amount, nil
+	}
+	rate, err := getExchangeRate(q, amount.Currency, currency, at)
+	if err != nil {
+		return Money{}, err
+	}
+	return rate.Convert(amount)
+}
+
+// GetLimits returns the limits of the user and how much of them is used in the periods containing at.
+func (s *PostgresqlStore) GetLimits(userID string, at time.Time) (*Limits, error) {
+	return s.getLimits(s.db, userID, at)
+}
+
+func (s *PostgresqlStore) getLimits(q querier, userID string, at time.Time) (*Limits, error) {
+	userLimits, err := s.getUserLimits(q, userID)
+	if err != nil {
+		return nil, err
+	}
+	limits := &Limits{UserID: userID, Custom: userLimits.Custom}
+	for _, period := range []LimitPeriod{DailyLimit, MonthlyLimit} {
+		limit := userLimits.Limit(period)
+		start := PeriodStart(period, at)
+		used := Zero(limit.Currency)
+		err := q.QueryRow(
+			`SELECT COALESCE(SUM(Amount), 0) FROM LimitUsage
+             WHERE UserID = $1 AND Currency = $2 AND Day >= $3 AND Day < $4`,
+			userID,
+			limit.Currency,
+			start,
+			PeriodEnd(period, start),
+		).Scan(&used.Amount)
+		if err != nil {
+			return nil, err
+		}
+		usage, err := NewLimitUsage(period, at, limit, used)
+		if err != nil {
+			return nil, err
+		}
+		if period == DailyLimit {
+			limits.Daily = usage
+		} else {
+			limits.Monthly = usage
+		}
+	}
+	return limits, nil
+}
+
+// getUserLimits returns the limits an admin set for the user, or the defaults if there are none.
+func (s *PostgresqlStore) getUserLimits(q querier, userID string) (*UserLimits, error) {
+	limits := &UserLimits{UserID: userID, Custom: true}
+	err := q.QueryRow(
+		`SELECT DailyLimit, MonthlyLimit, Currency, Updated FROM UserLimit WHERE UserID = $1`,
+		userID,
+	).Scan(&limits.Daily.Amount, &limits.Monthly.Amount, &limits.Daily.Currency, &limits.Updated)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			defaults := s.defaultLimits
+			defaults.UserID = userID
+			return &defaults, nil
+		}
+		return nil, err
+	}
+	limits.Monthly.Currency = limits.Daily.Currency
+	return limits, nil
+}
+
+// SetUserLimits gives the user limits of their own in place of the defaults.
+func (s *PostgresqlStore) SetUserLimits(limits *UserLimits, actorID string) error {
+	if limits.Daily.Currency != s.defaultLimits.Daily.Currency {
+		return errors.Wrapf(ErrCurrencyMismatch, "limits are kept in %s", s.defaultLimits.Daily.Currency)
+	}
+	return s.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`INSERT INTO UserLimit (UserID, DailyLimit, MonthlyLimit, Currency, Updated)
+             VALUES ($1, $2, $3, $4, $5)
+             ON CONFLICT (UserID) DO UPDATE
+             SET DailyLimit = EXCLUDED.DailyLimit, MonthlyLimit = EXCLUDED.MonthlyLimit, Currency = EXCLUDED.Currency, Updated = EXCLUDED.Updated`,
+			limits.UserID,
+			limits.Daily.Amount,
+			limits.Monthly.Amount,
+			limits.Daily.Currency,
+			limits.Updated,
+		)
+		if err != nil {
+			return err
+		}
+		details := "daily " + limits.Daily.Format() + ", monthly " + limits.Monthly.Format()
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "set_limits", "user", limits.UserID, details))
+	})
+}
+
+// ResetUserLimits puts the user back on the default limits.
+func (s *PostgresqlStore) ResetUserLimits(userID string, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM UserLimit WHERE UserID = $1`, userID); err != nil {
+			return err
+		}
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "reset_limits", "user", userID, "back to defaults"))
+	})
+}
@@ -0,0 +1,361 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+const loanColumns = `l.ID, l.AccountNumber, l.RepaymentAccount, l.OwnerID, l.Principal, l.Currency, l.AnnualRate, l.Term,
+                     l.Frequency, l.Method, l.LateFee, l.Status, COALESCE(l.DisbursementID, 0), l.Disbursed, l.Created, a.Balance`
+
+const loanFrom = `Loan l JOIN Account a ON a.AccountNumber = l.AccountNumber`
+
+// scanLoan reads a loan along with the balance of its loan account; the caller summarises it.
+func scanLoan(row rowScanner) (*Loan, Money, error) {
+	l := &Loan{}
+	var balance Money
+	err := row.Scan(
+		&l.ID,
+		&l.AccountNumber,
+		&l.RepaymentAccount,
+		&l.OwnerID,
+		&l.Principal.Amount,
+		&l.Principal.Currency,
+		&l.AnnualRate,
+		&l.Term,
+		&l.Frequency,
+		&l.Method,
+		&l.LateFee.Amount,
+		&l.Status,
+		&l.DisbursementID,
+		&l.Disbursed,
+		&l.Created,
+		&balance.Amount,
+	)
+	if err != nil {
+		return nil, balance, err
+	}
+	l.LateFee.Currency = l.Principal.Currency
+	balance.Currency = l.Principal.Currency
+	return l, balance, nil
+}
+
+const instalmentColumns = `ID, LoanID, Number, Due, Principal, Interest, LateFee, Currency, Status, Attempts, NextAttempt,
+                           COALESCE(TransactionID, 0), Paid`
+
+func scanLoanInstalment(row rowScanner) (*LoanInstalment, error) {
+	i := &LoanInstalment{}
+	var paid sql.NullTime
+	err := row.Scan(
+		&i.ID,
+		&i.LoanID,
+		&i.Number,
+		&i.Due,
+		&i.Principal.Amount,
+		&i.Interest.Amount,
+		&i.LateFee.Amount,
+		&i.Principal.Currency,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttempt,
+		&i.TransactionID,
+		&paid,
+	)
+	if err != nil {
+		return nil, err
+	}
+	i.Interest.Currency = i.Principal.Currency
+	i.LateFee.Currency = i.Principal.Currency
+	if i.Amount, err = i.Principal.Add(i.Interest); err != nil {
+		return nil, err
+	}
+	if paid.Valid {
+		i.Paid = &paid.Time
+	}
+	return i, nil
+}
+
+// CreateLoan opens a loan account for the owner, stores the loan with its schedule and pays the
+// principal into the repayment account, all in one database transaction.
+func (s *PostgresqlStore) CreateLoan(loan *Loan, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		locked, err := lockAccounts(tx, []string{loan.RepaymentAccount})
+		if err != nil {
+			return err
+		}
+		repayment, ok := locked[loan.RepaymentAccount]
+		if !ok || !repayment.Payable() {
+			return errors.Wrapf(ErrAccountNotFound, "%s", loan.RepaymentAccount)
+		}
+		if err := repayment.CheckCanPost(); err != nil {
+			return err
+		}
+		if repayment.Balance.Currency != loan.Principal.Currency {
+			return errors.Wrapf(ErrCurrencyMismatch, "account %s is held in %s", repayment.AccountNumber, repayment.Balance.Currency)
+		}
+
+		account := NewAccount(loan.OwnerID, loan.Principal.Currency)
+		account.Type = LoanAccount
+		if err := s.insertAccount(tx, account); err != nil {
+			return err
+		}
+		loan.AccountNumber = account.AccountNumber
+
+		disbursement := NewLoanDisbursement(loan)
+		if err := s.postTransaction(tx, disbursement); err != nil {
+			return err
+		}
+		loan.DisbursementID = disbursement.ID
+
+		err = tx.QueryRow(
+			`INSERT INTO Loan (AccountNumber, RepaymentAccount, OwnerID, Principal, Currency, AnnualRate, Term, Frequency,
+                               Method, LateFee, Status, DisbursementID, Disbursed, Created)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+             RETURNING ID`,
+			loan.AccountNumber,
+			loan.RepaymentAccount,
+			loan.OwnerID,
+			loan.Principal.Amount,
+			loan.Principal.Currency,
+			loan.AnnualRate,
+			loan.Term,
+			loan.Frequency,
+			loan.Method,
+			loan.LateFee.Amount,
+			loan.Status,
+			loan.DisbursementID,
+			loan.Disbursed,
+			loan.Created,
+		).Scan(&loan.ID)
+		if err != nil {
+			return err
+		}
+
+		instalments, err := loan.Schedule()
+		if err != nil {
+			return err
+		}
+		for _, i := range instalments {
+			i.LoanID = loan.ID
+			err := tx.QueryRow(
+				`INSERT INTO LoanInstalment (LoanID, Number, Due, Principal, Interest, LateFee, Currency, Status, NextAttempt)
+                 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+                 RETURNING ID`,
+				i.LoanID,
+				i.Number,
+				i.Due,
+				i.Principal.Amount,
+				i.Interest.Amount,
+				i.LateFee.Amount,
+				i.Principal.Currency,
+				i.Status,
+				i.NextAttempt,
+			).Scan(&i.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		details := loan.Principal.Format() + " to " + loan.RepaymentAccount
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "create_loan", "account", loan.AccountNumber, details))
+	})
+}
+
+// GetLoan returns the loan with its outstanding principal and arrears, or nil.
+func (s *PostgresqlStore) GetLoan(id int64) (*Loan, error) {
+	loan, err := getLoan(s.db, id, false)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return loan, nil
+}
+
+func getLoan(q querier, id int64, forUpdate bool) (*Loan, error) {
+	query := `SELECT ` + loanColumns + ` FROM ` + loanFrom + ` WHERE l.ID = $1`
+	if forUpdate {
+		query += ` FOR UPDATE OF l`
+	}
+	loan, balance, err := scanLoan(q.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+	instalments, err := getLoanInstalments(q, id)
+	if err != nil {
+		return nil, err
+	}
+	return loan, loan.Summarise(balance, instalments)
+}
+
+// GetLoans lists the loans repaid from an account, newest first.
+func (s *PostgresqlStore) GetLoans(repaymentAccount string) ([]*Loan, error) {
+	rows, err := s.db.Query(
+		`SELECT `+loanColumns+` FROM `+loanFrom+` WHERE l.RepaymentAccount = $1 ORDER BY l.Created DESC, l.ID DESC`,
+		repaymentAccount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []*Loan
+	balances := map[int64]Money{}
+	for rows.Next() {
+		loan, balance, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, loan)
+		balances[loan.ID] = balance
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, loan := range loans {
+		instalments, err := getLoanInstalments(s.db, loan.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := loan.Summarise(balances[loan.ID], instalments); err != nil {
+			return nil, err
+		}
+	}
+	return loans, nil
+}
+
+// GetLoanInstalments returns the repayment schedule of a loan.
+func (s *PostgresqlStore) GetLoanInstalments(loanID int64) ([]*LoanInstalment, error) {
+	return getLoanInstalments(s.db, loanID)
+}
+
+func getLoanInstalments(q querier, loanID int64) ([]*LoanInstalment, error) {
+	rows, err := q.Query(`SELECT `+instalmentColumns+` FROM LoanInstalment WHERE LoanID = $1 ORDER BY Number`, loanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instalments []*LoanInstalment
+	for rows.Next() {
+		i, err := scanLoanInstalment(rows)
+		if err != nil {
+			return nil, err
+		}
+		instalments = append(instalments, i)
+	}
+	return instalments, rows.Err()
+}
+
+// GetDueLoanInstalments returns up to limit unpaid instalments whose next collection attempt is due.
+func (s *PostgresqlStore) GetDueLoanInstalments(now time.Time, limit int) ([]int64, error) {
+	rows, err := s.db.Query(
+		`SELECT ID FROM LoanInstalment WHERE Status <> $1 AND NextAttempt <= $2 ORDER BY NextAttempt, LoanID, Number LIMIT $3`,
+		PaidInstalment,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CollectLoanInstalment takes the instalment from the repayment account if it is still due. A
+// collection the bank rejects (e.g. for insufficient funds) puts the instalment into arrears with
+// the late fee of the loan and is retried after retryDelay. The loan is repaid with its last
+// instalment. It returns nil when the instalment was not due or is being collected elsewhere.
+func (s *PostgresqlStore) CollectLoanInstalment(id int64, now time.Time, retryDelay time.Duration) (*LoanInstalment, error) {
+	var instalment *LoanInstalment
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		instalment, err = scanLoanInstalment(tx.QueryRow(
+			`SELECT `+instalmentColumns+` FROM LoanInstalment
+             WHERE ID = $1 AND Status <> $2 AND NextAttempt <= $3
+             FOR UPDATE SKIP LOCKED`,
+			id,
+			PaidInstalment,
+			now,
+		))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				instalment = nil
+				return nil
+			}
+			return err
+		}
+		loan, err := getLoan(tx, instalment.LoanID, true)
+		if err != nil {
+			return err
+		}
+		interestIncome, err := s.getSystemAccount(tx, InterestIncomeSystemAccount, loan.Principal.Currency)
+		if err != nil {
+			return err
+		}
+		feeIncome, err := s.getSystemAccount(tx, FeeIncomeSystemAccount, loan.Principal.Currency)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`SAVEPOINT payment`); err != nil {
+			return err
+		}
+		repayment, err := NewLoanRepayment(loan, instalment, interestIncome.AccountNumber, feeIncome.AccountNumber)
+		if err != nil {
+			return err
+		}
+		err = s.postTransaction(tx, repayment)
+		switch {
+		case err == nil:
+			instalment.RecordPayment(now, repayment.ID)
+		case isPaymentRejection(err):
+			if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT payment`); err != nil {
+				return err
+			}
+			instalment.RecordFailure(now, retryDelay, loan.LateFee)
+		default:
+			return err
+		}
+
+		_, err = tx.Exec(
+			`UPDATE LoanInstalment SET Status = $1, Attempts = $2, NextAttempt = $3, LateFee = $4, TransactionID = NULLIF($5, 0), Paid = $6
+             WHERE ID = $7`,
+			instalment.Status,
+			instalment.Attempts,
+			instalment.NextAttempt,
+			instalment.LateFee.Amount,
+			instalment.TransactionID,
+			instalment.Paid,
+			instalment.ID,
+		)
+		if err != nil || instalment.Status != PaidInstalment {
+			return err
+		}
+
+		// The loan is repaid once no instalment is left to pay.
+		_, err = tx.Exec(
+			`UPDATE Loan SET Status = $1
+             WHERE ID = $2 AND NOT EXISTS (SELECT 1 FROM LoanInstalment WHERE LoanID = $2 AND Status <> $3)`,
+			RepaidLoan,
+			loan.ID,
+			PaidInstalment,
+		)
+		return err
+	})
+	return instalment, err
+}
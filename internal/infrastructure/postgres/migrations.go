@@ -5,6 +5,7 @@ import (
 	"embed"
 	"github.com/pkg/errors"
 	"github.com/pressly/goose/v3"
+	. "go-bank-v2/internal/types"
 	"sync"
 )
 
@@ -14,11 +15,14 @@ var migrations embed.FS
 type Migrator struct {
 	adminSecret      string
 	createDefaultApp bool
+	accountPrefix    string
 	mx               sync.Mutex
 }
 
-func NewMigrator() *Migrator {
-	m := &Migrator{}
+func NewMigrator(accountPrefix string) *Migrator {
+	m := &Migrator{
+		accountPrefix: accountPrefix,
+	}
 	return m
 }
 
@@ -26,6 +30,9 @@ func (m *Migrator) Migrate(db *sql.DB) error {
 	if mErr := m.runMigrations(db); mErr != nil {
 		return mErr
 	}
+	if rErr := m.renumberLegacyAccounts(db); rErr != nil {
+		return rErr
+	}
 	return nil
 }
 
@@ -43,3 +50,50 @@ func (m *Migrator) runMigrations(db *sql.DB) error {
 
 	return nil
 }
+
+// renumberLegacyAccounts moves accounts created before check-digit account numbers onto the
+// configured prefix. The old number is kept in LegacyNumber and references follow through
+// ON UPDATE CASCADE foreign keys.
+func (m *Migrator) renumberLegacyAccounts(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.Query(`SELECT AccountNumber FROM Account WHERE LegacyNumber IS NULL AND length(AccountNumber) <= 10 FOR UPDATE`)
+	if err != nil {
+		return errors.Wrap(err, "listing legacy account numbers")
+	}
+	var legacy []string
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			rows.Close()
+			return err
+		}
+		legacy = append(legacy, number)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, old := range legacy {
+		var serial int64
+		if err := tx.QueryRow(`SELECT nextval('AccountSerial')`).Scan(&serial); err != nil {
+			return err
+		}
+		number, err := FormatAccountNumber(m.accountPrefix, serial)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE Account SET AccountNumber = $1, LegacyNumber = $2 WHERE AccountNumber = $2`, number, old)
+		if err != nil {
+			return errors.Wrapf(err, "renumbering account %s", old)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+)
+
+// SetOverdraftLimit changes how far below zero the account balance may go. A zero limit removes the overdraft.
+func (s *PostgresqlStore) SetOverdraftLimit(accountNumber string, limit Money, actorID string) (*Account, error) {
+	var account *Account
+	err := s.withTx(func(tx *sql.Tx) error {
+		locked, err := lockAccounts(tx, []string{accountNumber})
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if account, ok = locked[accountNumber]; !ok || !account.Payable() {
+			return errors.Wrapf(ErrAccountNotFound, "%s", accountNumber)
+		}
+		previous := account.OverdraftLimit
+		if err := account.SetOverdraftLimit(limit); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE Account SET OverdraftLimit = $1 WHERE AccountNumber = $2`, limit.Amount, accountNumber); err != nil {
+			return err
+		}
+		details := previous.Format() + " -> " + limit.Format()
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "set_overdraft_limit", "account", accountNumber, details))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
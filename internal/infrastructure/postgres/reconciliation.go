@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+// CreateReconciliationRun records the start of a run.
+func (s *PostgresqlStore) CreateReconciliationRun(run *ReconciliationRun) error {
+	return s.db.QueryRow(
+		`INSERT INTO ReconciliationRun (Status, Started) VALUES ($1, $2) RETURNING ID`,
+		run.Status,
+		run.Started,
+	).Scan(&run.ID)
+}
+
+// ReconcileBalances compares the stored balances of the given accounts with the sums of their
+// ledger entries and records the accounts that disagree with the run. Both come from the same
+// snapshot of a single statement, so postings running alongside can't show up as mismatches and
+// no account is locked.
+func (s *PostgresqlStore) ReconcileBalances(run *ReconciliationRun, accountNumbers []string) ([]*BalanceMismatch, error) {
+	var mismatches []*BalanceMismatch
+	err := s.withTx(func(tx *sql.Tx) error {
+		rows, err := tx.Query(
+			`SELECT a.AccountNumber, a.Balance, a.Currency,
+                    COALESCE((SELECT SUM(CASE WHEN e.Direction = 'credit' THEN e.Amount ELSE -e.Amount END)
+                              FROM LedgerEntry e WHERE e.AccountNumber = a.AccountNumber), 0)
+             FROM Account a
+             WHERE a.AccountNumber = ANY($1)`,
+			pq.Array(accountNumbers),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		checked := time.Now()
+		for rows.Next() {
+			var accountNumber string
+			var stored, computed Money
+			if err := rows.Scan(&accountNumber, &stored.Amount, &stored.Currency, &computed.Amount); err != nil {
+				return err
+			}
+			computed.Currency = stored.Currency
+			mismatch, err := NewBalanceMismatch(run.ID, accountNumber, stored, computed, checked)
+			if err != nil {
+				return err
+			}
+			if mismatch != nil {
+				mismatches = append(mismatches, mismatch)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, m := range mismatches {
+			_, err := tx.Exec(
+				`INSERT INTO BalanceMismatch (RunID, AccountNumber, Stored, Computed, Currency, Checked)
+                 VALUES ($1, $2, $3, $4, $5, $6)
+                 ON CONFLICT (RunID, AccountNumber) DO NOTHING`,
+				m.RunID,
+				m.AccountNumber,
+				m.Stored.Amount,
+				m.Computed.Amount,
+				m.Stored.Currency,
+				m.Checked,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec(`UPDATE ReconciliationRun SET Accounts = Accounts + $1 WHERE ID = $2`, len(accountNumbers), run.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mismatches, nil
+}
+
+// FinishReconciliationRun records the outcome of a run.
+func (s *PostgresqlStore) FinishReconciliationRun(run *ReconciliationRun) error {
+	_, err := s.db.Exec(
+		`UPDATE ReconciliationRun SET Status = $1, Error = $2, Finished = $3 WHERE ID = $4`,
+		run.Status,
+		run.Error,
+		run.Finished,
+		run.ID,
+	)
+	return err
+}
+
+// GetReconciliationRun returns the run with the mismatches found so far, or nil.
+func (s *PostgresqlStore) GetReconciliationRun(id int64) (*ReconciliationRun, error) {
+	run := &ReconciliationRun{Mismatches: []*BalanceMismatch{}}
+	var finished sql.NullTime
+	err := s.db.QueryRow(
+		`SELECT ID, Status, Accounts, Error, Started, Finished FROM ReconciliationRun WHERE ID = $1`,
+		id,
+	).Scan(&run.ID, &run.Status, &run.Accounts, &run.Error, &run.Started, &finished)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if finished.Valid {
+		run.Finished = &finished.Time
+	}
+
+	rows, err := s.db.Query(
+		`SELECT RunID, AccountNumber, Stored, Computed, Currency, Checked
+         FROM BalanceMismatch WHERE RunID = $1 ORDER BY AccountNumber`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := &BalanceMismatch{}
+		err := rows.Scan(&m.RunID, &m.AccountNumber, &m.Stored.Amount, &m.Computed.Amount, &m.Stored.Currency, &m.Checked)
+		if err != nil {
+			return nil, err
+		}
+		m.Computed.Currency = m.Stored.Currency
+		if m.Difference, err = m.Stored.Sub(m.Computed); err != nil {
+			return nil, err
+		}
+		run.Mismatches = append(run.Mismatches, m)
+	}
+	return run, rows.Err()
+}
@@ -2,43 +2,112 @@ package postgres
 
 import (
 	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	. "go-bank-v2/internal/types"
+	"strings"
 )
 
+const accountColumns = `AccountNumber, Balance, Held, OverdraftLimit, Currency, Type, Status, OwnerID, Created`
+
+func prefixColumns(prefix string, columns string) string {
+	parts := strings.Split(columns, ", ")
+	for i, column := range parts {
+		parts[i] = prefix + column
+	}
+	return strings.Join(parts, ", ")
+}
+
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAccount(row rowScanner) (*Account, error) {
+	account := &Account{}
+	err := row.Scan(
+		&account.AccountNumber,
+		&account.Balance.Amount,
+		&account.Held.Amount,
+		&account.OverdraftLimit.Amount,
+		&account.Balance.Currency,
+		&account.Type,
+		&account.Status,
+		&account.OwnerID,
+		&account.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	account.Held.Currency = account.Balance.Currency
+	account.OverdraftLimit.Currency = account.Balance.Currency
+	if account.Available, err = account.AvailableBalance(); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
 func (s *PostgresqlStore) CreateAccount(acc *Account) error {
-	query := `INSERT INTO Account (Balance, OwnerID, Created)
-              VALUES ($1, $2, $3)
-              RETURNING AccountNumber`
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.insertAccount(tx, acc)
+	})
+}
 
-	err := s.db.QueryRow(
+// insertAccount assigns the next account number under the bank prefix and stores the account.
+func (s *PostgresqlStore) insertAccount(q querier, acc *Account) error {
+	var serial int64
+	if err := q.QueryRow(`SELECT nextval('AccountSerial')`).Scan(&serial); err != nil {
+		return err
+	}
+	accountNumber, err := FormatAccountNumber(s.accountPrefix, serial)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO Account (AccountNumber, Balance, Currency, Type, Status, OwnerID, Created)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = q.Exec(
 		query,
-		acc.Balance,
+		accountNumber,
+		acc.Balance.Amount,
+		acc.Balance.Currency,
+		acc.Type,
+		acc.Status,
 		acc.OwnerID,
 		acc.Created,
-	).Scan(&acc.AccountNumber)
-
+	)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	acc.AccountNumber = accountNumber
+	if acc.Type == InternalAccount {
+		return nil
+	}
+	return insertAccountHolder(q, ownerHolder(acc))
 }
 
 func (s *PostgresqlStore) CreateUser(user *User) error {
-	query := `INSERT INTO "User" (FirstName, LastName, MemberSince, EncryptedPassword, Role)
-              VALUES ($1, $2, $3, $4, $5)
-              RETURNING ID`
+	query := `INSERT INTO "User" (ID, FirstName, LastName, MemberSince, EncryptedPassword, Role, Active)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
-	// Execute the query and scan the result into user.ID
-	err := s.db.QueryRow(
+	_, err := s.db.Exec(
 		query,
+		user.ID,
 		user.FirstName,
 		user.LastName,
 		user.MemberSince,
 		user.EncryptedPassword,
 		user.Role,
-	).Scan(&user.ID)
+		user.Active,
+	)
 
 	if err != nil {
 		return err
@@ -47,8 +116,8 @@ func (s *PostgresqlStore) CreateUser(user *User) error {
 	return nil
 }
 
-func (s *PostgresqlStore) GetUserByID(userID int) (*User, error) {
-	query := `SELECT ID, FirstName, LastName, MemberSince, EncryptedPassword, Role FROM "User" WHERE ID = $1`
+func (s *PostgresqlStore) GetUserByID(userID string) (*User, error) {
+	query := `SELECT ID, FirstName, LastName, MemberSince, EncryptedPassword, Role, Active FROM "User" WHERE ID = $1`
 
 	user := &User{}
 
@@ -59,6 +128,7 @@ func (s *PostgresqlStore) GetUserByID(userID int) (*User, error) {
 		&user.MemberSince,
 		&user.EncryptedPassword,
 		&user.Role,
+		&user.Active,
 	)
 
 	if err != nil {
@@ -72,26 +142,62 @@ func (s *PostgresqlStore) GetUserByID(userID int) (*User, error) {
 	return user, nil
 }
 
-func (s *PostgresqlStore) UpdateAccountBalance(acc *Account, newBalance float64) error {
-	query := `UPDATE Account SET balance = $1 WHERE accountnumber = $2`
+// AdjustAccountBalance brings the account to newBalance by posting the difference against the
+// adjustment system account. The adjustment is audited together with the acting admin and reason.
+func (s *PostgresqlStore) AdjustAccountBalance(acc *Account, newBalance Money, reason string, actorID string) (*Transaction, error) {
+	var t *Transaction
+	err := s.withTx(func(tx *sql.Tx) error {
+		adjustment, err := s.getSystemAccount(tx, AdjustmentSystemAccount, newBalance.Currency)
+		if err != nil {
+			return err
+		}
+		locked, err := lockAccounts(tx, []string{acc.AccountNumber, adjustment.AccountNumber})
+		if err != nil {
+			return err
+		}
+		current, ok := locked[acc.AccountNumber]
+		if !ok {
+			return errors.Wrapf(ErrAccountNotFound, "%s", acc.AccountNumber)
+		}
 
-	_, err := s.db.Exec(query, newBalance, acc.AccountNumber)
-	if err != nil {
-		return err
-	}
+		diff, err := newBalance.Sub(current.Balance)
+		if err != nil {
+			return err
+		}
+		if diff.IsZero() {
+			return nil
+		}
 
-	return nil
-}
+		t = NewTransaction(AdjustmentTransaction, "", reason)
+		if diff.IsPositive() {
+			t.Debit(adjustment.AccountNumber, diff).Credit(acc.AccountNumber, diff)
+		} else {
+			t.Debit(acc.AccountNumber, diff.Abs()).Credit(adjustment.AccountNumber, diff.Abs())
+		}
+		if err := s.postTransaction(tx, t); err != nil {
+			return err
+		}
 
-func (s *PostgresqlStore) DeleteAccount(id int) error {
-	_, err := s.db.Query("delete from account where accountnumber = $1", id)
-	return err
+		event := NewAuditEvent(
+			actorID,
+			"adjust_balance",
+			"account",
+			acc.AccountNumber,
+			fmt.Sprintf("balance %s -> %s (transaction %d): %s", current.Balance.Format(), newBalance.Format(), t.ID, reason),
+		)
+		return recordAuditEvent(tx, event)
+	})
+	return t, err
 }
 
-func (s *PostgresqlStore) GetAccounts(ownerID int) ([]*Account, error) {
-	query := `SELECT * FROM Account WHERE ownerId = $1`
+// GetAccounts returns the accounts a user holds in any role, optionally only those in one of the given statuses.
+func (s *PostgresqlStore) GetAccounts(userID string, statuses ...AccountStatus) ([]*Account, error) {
+	query := `SELECT ` + prefixColumns("a.", accountColumns) + `
+              FROM Account a JOIN AccountHolder h ON h.AccountNumber = a.AccountNumber
+              WHERE h.UserID = $1 AND (cardinality($2::text[]) = 0 OR a.Status = ANY($2))
+              ORDER BY a.AccountNumber`
 
-	rows, err := s.db.Query(query, ownerID)
+	rows, err := s.db.Query(query, userID, pq.Array(statusStrings(statuses)))
 	if err != nil {
 		return nil, err
 	}
@@ -105,13 +211,7 @@ func (s *PostgresqlStore) GetAccounts(ownerID int) ([]*Account, error) {
 	var accounts []*Account
 
 	for rows.Next() {
-		account := &Account{}
-		err := rows.Scan(
-			&account.AccountNumber,
-			&account.Balance,
-			&account.OwnerID,
-			&account.Created,
-		)
+		account, err := scanAccount(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -125,17 +225,10 @@ func (s *PostgresqlStore) GetAccounts(ownerID int) ([]*Account, error) {
 	return accounts, nil
 }
 
-func (s *PostgresqlStore) GetAccountByNumber(accountNumber int) (*Account, error) {
-	query := `SELECT * FROM Account WHERE accountNumber = $1`
+func (s *PostgresqlStore) GetAccountByNumber(accountNumber string) (*Account, error) {
+	query := `SELECT ` + accountColumns + ` FROM Account WHERE accountNumber = $1`
 
-	account := &Account{}
-
-	err := s.db.QueryRow(query, accountNumber).Scan(
-		&account.AccountNumber,
-		&account.Balance,
-		&account.OwnerID,
-		&account.Created,
-	)
+	account, err := scanAccount(s.db.QueryRow(query, accountNumber))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -148,15 +241,12 @@ func (s *PostgresqlStore) GetAccountByNumber(accountNumber int) (*Account, error
 	return account, nil
 }
 
-func (s *PostgresqlStore) DeleteUser(userId int) error {
-	_, err := s.db.Query(`DELETE FROM "User" WHERE id = $1`, userId)
-	return err
-}
-
-func (s *PostgresqlStore) GetAllAccounts() ([]*Account, error) {
-	query := `SELECT * FROM Account`
+// GetAllAccounts returns every account, optionally only those in one of the given statuses.
+func (s *PostgresqlStore) GetAllAccounts(statuses ...AccountStatus) ([]*Account, error) {
+	query := `SELECT ` + accountColumns + ` FROM Account
+              WHERE cardinality($1::text[]) = 0 OR Status = ANY($1)`
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.Query(query, pq.Array(statusStrings(statuses)))
 	if err != nil {
 		return nil, err
 	}
@@ -170,13 +260,7 @@ func (s *PostgresqlStore) GetAllAccounts() ([]*Account, error) {
 	var accounts []*Account
 
 	for rows.Next() {
-		account := &Account{}
-		err := rows.Scan(
-			&account.AccountNumber,
-			&account.Balance,
-			&account.OwnerID,
-			&account.Created,
-		)
+		account, err := scanAccount(rows)
 		if err != nil {
 			return nil, err
 		}
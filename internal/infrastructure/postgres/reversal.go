@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"strconv"
+)
+
+// ReverseTransaction books the compensating entries for all (amount nil) or part of a transaction.
+// Reversals of the same transaction are serialised, so the original can never be reversed beyond
+// its own amounts. Postings of fees, holds, loans and term deposits are left to their own operations.
+func (s *PostgresqlStore) ReverseTransaction(id int64, amount *Money, reason string, actorID string) (*Transaction, error) {
+	var reversal *Transaction
+	err := s.withTx(func(tx *sql.Tx) error {
+		var txType TransactionType
+		err := tx.QueryRow(`SELECT Type FROM LedgerTransaction WHERE ID = $1`, id).Scan(&txType)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrTransactionNotFound, "%d", id)
+			}
+			return err
+		}
+		if err := txType.CheckReversible(); err != nil {
+			return errors.Wrapf(err, "transaction %d", id)
+		}
+		if reversal, err = s.reverseTransaction(tx, id, amount, reason); err != nil {
+			return err
+		}
+		details := fmt.Sprintf("reversal %d: %s", reversal.ID, reason)
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "reverse", "transaction", strconv.FormatInt(id, 10), details))
+	})
+	return reversal, err
+}
+
+func (s *PostgresqlStore) reverseTransaction(tx *sql.Tx, id int64, amount *Money, reason string) (*Transaction, error) {
+	var locked int64
+	err := tx.QueryRow(`SELECT ID FROM LedgerTransaction WHERE ID = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(ErrTransactionNotFound, "%d", id)
+		}
+		return nil, err
+	}
+	original, err := getTransaction(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := getReversals(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reversal, err := NewReversal(original, previous, amount, reason)
+	if err != nil {
+		return nil, err
+	}
+	return reversal, s.postTransaction(tx, reversal)
+}
+
+// GetReversals returns the reversals of a transaction, oldest first.
+func (s *PostgresqlStore) GetReversals(id int64) ([]*Transaction, error) {
+	return getReversals(s.db, id)
+}
+
+func getReversals(q querier, id int64) ([]*Transaction, error) {
+	rows, err := q.Query(`SELECT ID FROM LedgerTransaction WHERE ReversalOf = $1 ORDER BY ID`, id)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var reversalID int64
+		if err := rows.Scan(&reversalID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, reversalID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	reversals := make([]*Transaction, 0, len(ids))
+	for _, reversalID := range ids {
+		reversal, err := getTransaction(q, reversalID)
+		if err != nil {
+			return nil, err
+		}
+		reversals = append(reversals, reversal)
+	}
+	return reversals, nil
+}
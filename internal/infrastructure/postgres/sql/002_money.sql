@@ -0,0 +1,8 @@
+-- +goose Up
+-- Balances are stored as integer minor units of the account currency
+ALTER TABLE Account ADD COLUMN IF NOT EXISTS Currency text NOT NULL DEFAULT 'EUR';
+ALTER TABLE Account ALTER COLUMN Balance TYPE bigint USING round(Balance::numeric * 100)::bigint;
+
+-- +goose Down
+ALTER TABLE Account ALTER COLUMN Balance TYPE real USING (Balance / 100.0)::real;
+ALTER TABLE Account DROP COLUMN IF EXISTS Currency;
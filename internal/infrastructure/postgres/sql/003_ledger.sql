@@ -0,0 +1,68 @@
+-- +goose Up
+-- Every balance change is recorded as a balanced set of ledger entries
+ALTER TABLE Account ADD COLUMN IF NOT EXISTS Type text NOT NULL DEFAULT 'current';
+
+CREATE TABLE IF NOT EXISTS SystemAccount (
+    Code text NOT NULL,
+    Currency text NOT NULL,
+    AccountNumber int NOT NULL REFERENCES Account (AccountNumber),
+    PRIMARY KEY (Code, Currency)
+);
+
+CREATE TABLE IF NOT EXISTS LedgerTransaction (
+    ID bigserial PRIMARY KEY,
+    Type text NOT NULL,
+    Reference text NOT NULL DEFAULT '',
+    Description text NOT NULL DEFAULT '',
+    Created timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS LedgerEntry (
+    ID bigserial PRIMARY KEY,
+    TransactionID bigint NOT NULL REFERENCES LedgerTransaction (ID),
+    AccountNumber int NOT NULL REFERENCES Account (AccountNumber),
+    Direction text NOT NULL CHECK (Direction IN ('debit', 'credit')),
+    Amount bigint NOT NULL CHECK (Amount > 0),
+    Currency text NOT NULL,
+    Created timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS LedgerEntry_Account_Created ON LedgerEntry (AccountNumber, Created);
+CREATE INDEX IF NOT EXISTS LedgerEntry_Transaction ON LedgerEntry (TransactionID);
+
+-- Carry existing balances into the ledger against the opening balance system account
+-- +goose StatementBegin
+DO $$
+DECLARE
+    acc record;
+    equity int;
+    txn bigint;
+BEGIN
+    FOR acc IN SELECT AccountNumber, Balance, Currency FROM Account WHERE Balance <> 0 AND Type = 'current' ORDER BY AccountNumber LOOP
+        SELECT AccountNumber INTO equity FROM SystemAccount WHERE Code = 'opening_balance' AND Currency = acc.Currency;
+        IF equity IS NULL THEN
+            INSERT INTO Account (Balance, Currency, Type, OwnerID, Created)
+            VALUES (0, acc.Currency, 'internal', 0, now())
+            RETURNING AccountNumber INTO equity;
+            INSERT INTO SystemAccount (Code, Currency, AccountNumber) VALUES ('opening_balance', acc.Currency, equity);
+        END IF;
+
+        INSERT INTO LedgerTransaction (Type, Description, Created)
+        VALUES ('opening_balance', 'Balance carried over from before the ledger', now())
+        RETURNING ID INTO txn;
+
+        INSERT INTO LedgerEntry (TransactionID, AccountNumber, Direction, Amount, Currency, Created) VALUES
+            (txn, acc.AccountNumber, CASE WHEN acc.Balance > 0 THEN 'credit' ELSE 'debit' END, abs(acc.Balance), acc.Currency, now()),
+            (txn, equity, CASE WHEN acc.Balance > 0 THEN 'debit' ELSE 'credit' END, abs(acc.Balance), acc.Currency, now());
+
+        UPDATE Account SET Balance = Balance - acc.Balance WHERE AccountNumber = equity;
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS LedgerEntry;
+DROP TABLE IF EXISTS LedgerTransaction;
+DROP TABLE IF EXISTS SystemAccount;
+DELETE FROM Account WHERE Type = 'internal';
+ALTER TABLE Account DROP COLUMN IF EXISTS Type;
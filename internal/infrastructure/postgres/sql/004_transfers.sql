@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS Transfer (
+    ID bigserial PRIMARY KEY,
+    FromAccount int NOT NULL REFERENCES Account (AccountNumber),
+    ToAccount int NOT NULL REFERENCES Account (AccountNumber),
+    Amount bigint NOT NULL CHECK (Amount > 0),
+    Currency text NOT NULL,
+    Reference text NOT NULL DEFAULT '',
+    TransactionID bigint NOT NULL REFERENCES LedgerTransaction (ID),
+    InitiatedBy int NOT NULL,
+    Created timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS Transfer_FromAccount ON Transfer (FromAccount);
+CREATE INDEX IF NOT EXISTS Transfer_ToAccount ON Transfer (ToAccount);
+
+-- +goose Down
+DROP TABLE IF EXISTS Transfer;
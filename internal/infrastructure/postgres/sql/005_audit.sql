@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS AuditEvent (
+    ID bigserial PRIMARY KEY,
+    ActorID int NOT NULL,
+    Action text NOT NULL,
+    Entity text NOT NULL,
+    EntityID text NOT NULL,
+    Details text NOT NULL DEFAULT '',
+    Created timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS AuditEvent_Entity ON AuditEvent (Entity, EntityID);
+
+-- +goose Down
+DROP TABLE IF EXISTS AuditEvent;
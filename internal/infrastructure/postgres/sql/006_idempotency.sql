@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS IdempotencyKey (
+    Key text NOT NULL,
+    CallerID int NOT NULL,
+    Fingerprint text NOT NULL,
+    Completed boolean NOT NULL DEFAULT false,
+    StatusCode int NOT NULL DEFAULT 0,
+    Body bytea NOT NULL,
+    Created timestamp NOT NULL,
+    Expires timestamp NOT NULL,
+    PRIMARY KEY (Key, CallerID)
+);
+
+CREATE INDEX IF NOT EXISTS IdempotencyKey_Expires ON IdempotencyKey (Expires);
+
+-- +goose Down
+DROP TABLE IF EXISTS IdempotencyKey;
@@ -0,0 +1,63 @@
+-- +goose Up
+-- Account numbers become prefix + serial + MOD 97-10 check digits and user IDs become random UUIDs.
+-- Existing accounts are renumbered by the application right after migrating (see renumberLegacyAccounts),
+-- because the prefix is configuration rather than schema.
+DROP TRIGGER IF EXISTS set_account_number ON Account;
+DROP TRIGGER IF EXISTS set_user_id ON "User";
+DROP FUNCTION IF EXISTS set_random_account_number();
+DROP FUNCTION IF EXISTS set_random_user_id();
+DROP FUNCTION IF EXISTS random_int(int, int);
+
+CREATE SEQUENCE IF NOT EXISTS AccountSerial;
+
+ALTER TABLE SystemAccount DROP CONSTRAINT IF EXISTS systemaccount_accountnumber_fkey;
+ALTER TABLE LedgerEntry DROP CONSTRAINT IF EXISTS ledgerentry_accountnumber_fkey;
+ALTER TABLE Transfer DROP CONSTRAINT IF EXISTS transfer_fromaccount_fkey;
+ALTER TABLE Transfer DROP CONSTRAINT IF EXISTS transfer_toaccount_fkey;
+
+ALTER TABLE Account ALTER COLUMN AccountNumber DROP DEFAULT;
+ALTER TABLE Account ALTER COLUMN AccountNumber TYPE text USING AccountNumber::text;
+ALTER TABLE Account ADD COLUMN IF NOT EXISTS LegacyNumber text;
+DROP SEQUENCE IF EXISTS account_accountnumber_seq;
+ALTER TABLE SystemAccount ALTER COLUMN AccountNumber TYPE text USING AccountNumber::text;
+ALTER TABLE LedgerEntry ALTER COLUMN AccountNumber TYPE text USING AccountNumber::text;
+ALTER TABLE Transfer ALTER COLUMN FromAccount TYPE text USING FromAccount::text;
+ALTER TABLE Transfer ALTER COLUMN ToAccount TYPE text USING ToAccount::text;
+
+ALTER TABLE SystemAccount ADD CONSTRAINT systemaccount_accountnumber_fkey
+    FOREIGN KEY (AccountNumber) REFERENCES Account (AccountNumber) ON UPDATE CASCADE;
+ALTER TABLE LedgerEntry ADD CONSTRAINT ledgerentry_accountnumber_fkey
+    FOREIGN KEY (AccountNumber) REFERENCES Account (AccountNumber) ON UPDATE CASCADE;
+ALTER TABLE Transfer ADD CONSTRAINT transfer_fromaccount_fkey
+    FOREIGN KEY (FromAccount) REFERENCES Account (AccountNumber) ON UPDATE CASCADE;
+ALTER TABLE Transfer ADD CONSTRAINT transfer_toaccount_fkey
+    FOREIGN KEY (ToAccount) REFERENCES Account (AccountNumber) ON UPDATE CASCADE;
+
+-- Give every existing user a UUID and carry it over to everything referring to the old ID
+ALTER TABLE "User" ADD COLUMN NewID text;
+UPDATE "User" SET NewID = gen_random_uuid()::text;
+
+ALTER TABLE Account ALTER COLUMN OwnerID TYPE text USING OwnerID::text;
+UPDATE Account a SET OwnerID = u.NewID FROM "User" u WHERE a.OwnerID = u.ID::text;
+UPDATE Account SET OwnerID = '' WHERE Type = 'internal';
+
+ALTER TABLE Transfer ALTER COLUMN InitiatedBy TYPE text USING InitiatedBy::text;
+UPDATE Transfer t SET InitiatedBy = u.NewID FROM "User" u WHERE t.InitiatedBy = u.ID::text;
+
+ALTER TABLE AuditEvent ALTER COLUMN ActorID TYPE text USING ActorID::text;
+UPDATE AuditEvent e SET ActorID = u.NewID FROM "User" u WHERE e.ActorID = u.ID::text;
+
+DELETE FROM IdempotencyKey;
+ALTER TABLE IdempotencyKey ALTER COLUMN CallerID TYPE text USING '';
+
+ALTER TABLE "User" DROP CONSTRAINT "User_pkey";
+ALTER TABLE "User" DROP COLUMN ID;
+ALTER TABLE "User" RENAME COLUMN NewID TO ID;
+ALTER TABLE "User" ALTER COLUMN ID SET NOT NULL;
+ALTER TABLE "User" ADD PRIMARY KEY (ID);
+
+CREATE INDEX IF NOT EXISTS Account_OwnerID ON Account (OwnerID);
+
+-- +goose Down
+-- Numeric identifiers cannot be restored once accounts and users have been renumbered.
+SELECT 1;
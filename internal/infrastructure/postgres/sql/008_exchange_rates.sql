@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS ExchangeRate (
+    ID bigserial PRIMARY KEY,
+    Base text NOT NULL,
+    Quote text NOT NULL,
+    Rate numeric(24, 10) NOT NULL CHECK (Rate > 0),
+    ValidFrom timestamp NOT NULL,
+    Created timestamp NOT NULL,
+    UNIQUE (Base, Quote, ValidFrom)
+);
+
+ALTER TABLE LedgerTransaction ADD COLUMN IF NOT EXISTS ExchangeRate text NOT NULL DEFAULT '';
+
+ALTER TABLE Transfer ADD COLUMN IF NOT EXISTS ConvertedAmount bigint;
+ALTER TABLE Transfer ADD COLUMN IF NOT EXISTS ConvertedCurrency text;
+ALTER TABLE Transfer ADD COLUMN IF NOT EXISTS ExchangeRate text NOT NULL DEFAULT '';
+UPDATE Transfer SET ConvertedAmount = Amount, ConvertedCurrency = Currency WHERE ConvertedAmount IS NULL;
+ALTER TABLE Transfer ALTER COLUMN ConvertedAmount SET NOT NULL;
+ALTER TABLE Transfer ALTER COLUMN ConvertedCurrency SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE Transfer DROP COLUMN IF EXISTS ExchangeRate;
+ALTER TABLE Transfer DROP COLUMN IF EXISTS ConvertedCurrency;
+ALTER TABLE Transfer DROP COLUMN IF EXISTS ConvertedAmount;
+ALTER TABLE LedgerTransaction DROP COLUMN IF EXISTS ExchangeRate;
+DROP TABLE IF EXISTS ExchangeRate;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE Account ADD COLUMN IF NOT EXISTS Status text NOT NULL DEFAULT 'active'
+    CHECK (Status IN ('active', 'frozen', 'dormant', 'closed'));
+ALTER TABLE "User" ADD COLUMN IF NOT EXISTS Active boolean NOT NULL DEFAULT true;
+
+CREATE INDEX IF NOT EXISTS Account_Status ON Account (Status);
+
+-- +goose Down
+ALTER TABLE "User" DROP COLUMN IF EXISTS Active;
+ALTER TABLE Account DROP COLUMN IF EXISTS Status;
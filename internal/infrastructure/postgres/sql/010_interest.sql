@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS InterestRate (
+    ID bigserial PRIMARY KEY,
+    AccountNumber text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    AnnualRate numeric(12, 8) NOT NULL CHECK (AnnualRate >= 0),
+    ValidFrom timestamp NOT NULL,
+    Created timestamp NOT NULL,
+    UNIQUE (AccountNumber, ValidFrom)
+);
+
+CREATE TABLE IF NOT EXISTS InterestAccrual (
+    AccountNumber text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    Day date NOT NULL,
+    Balance bigint NOT NULL,
+    Currency text NOT NULL,
+    AnnualRate numeric(12, 8) NOT NULL,
+    DayCount text NOT NULL,
+    Amount numeric(30, 10) NOT NULL,
+    TransactionID bigint REFERENCES LedgerTransaction (ID),
+    Capitalised timestamp,
+    Created timestamp NOT NULL,
+    PRIMARY KEY (AccountNumber, Day)
+);
+
+CREATE INDEX IF NOT EXISTS InterestAccrual_Pending ON InterestAccrual (AccountNumber, Day) WHERE Capitalised IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS InterestAccrual;
+DROP TABLE IF EXISTS InterestRate;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS StandingOrder (
+    ID bigserial PRIMARY KEY,
+    OwnerID text NOT NULL,
+    FromAccount text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    ToAccount text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    Amount bigint NOT NULL CHECK (Amount > 0),
+    Currency text NOT NULL,
+    Reference text NOT NULL DEFAULT '',
+    Frequency text NOT NULL CHECK (Frequency IN ('daily', 'weekly', 'monthly')),
+    DayOfMonth integer NOT NULL DEFAULT 0,
+    StartDate date NOT NULL,
+    EndDate date,
+    NextRun date NOT NULL,
+    NextAttempt timestamp NOT NULL,
+    Attempts integer NOT NULL DEFAULT 0,
+    Status text NOT NULL CHECK (Status IN ('active', 'completed', 'cancelled')),
+    Created timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS StandingOrder_Owner ON StandingOrder (OwnerID);
+CREATE INDEX IF NOT EXISTS StandingOrder_Due ON StandingOrder (NextAttempt) WHERE Status = 'active';
+
+CREATE TABLE IF NOT EXISTS StandingOrderExecution (
+    ID bigserial PRIMARY KEY,
+    OrderID bigint NOT NULL REFERENCES StandingOrder (ID),
+    Due date NOT NULL,
+    Attempt integer NOT NULL,
+    Status text NOT NULL,
+    TransferID bigint REFERENCES Transfer (ID),
+    Error text NOT NULL DEFAULT '',
+    Created timestamp NOT NULL,
+    UNIQUE (OrderID, Due, Attempt)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS StandingOrderExecution;
+DROP TABLE IF EXISTS StandingOrder;
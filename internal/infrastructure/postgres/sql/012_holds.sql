@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE Account ADD COLUMN IF NOT EXISTS Held bigint NOT NULL DEFAULT 0 CHECK (Held >= 0);
+
+CREATE TABLE IF NOT EXISTS Hold (
+    ID bigserial PRIMARY KEY,
+    AccountNumber text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    Amount bigint NOT NULL CHECK (Amount > 0),
+    Captured bigint NOT NULL DEFAULT 0 CHECK (Captured >= 0 AND Captured <= Amount),
+    Currency text NOT NULL,
+    Reference text NOT NULL DEFAULT '',
+    Status text NOT NULL CHECK (Status IN ('active', 'captured', 'released', 'expired')),
+    InitiatedBy text NOT NULL,
+    Expires timestamp NOT NULL,
+    Created timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS Hold_Account ON Hold (AccountNumber);
+CREATE INDEX IF NOT EXISTS Hold_Expires ON Hold (Expires) WHERE Status = 'active';
+
+-- +goose Down
+DROP TABLE IF EXISTS Hold;
+ALTER TABLE Account DROP COLUMN IF EXISTS Held;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE LedgerTransaction ADD COLUMN IF NOT EXISTS ReversalOf bigint REFERENCES LedgerTransaction (ID);
+CREATE INDEX IF NOT EXISTS LedgerTransaction_ReversalOf ON LedgerTransaction (ReversalOf) WHERE ReversalOf IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS LedgerTransaction_ReversalOf;
+ALTER TABLE LedgerTransaction DROP COLUMN IF EXISTS ReversalOf;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE Account ADD COLUMN IF NOT EXISTS OverdraftLimit bigint NOT NULL DEFAULT 0 CHECK (OverdraftLimit >= 0);
+
+-- +goose Down
+ALTER TABLE Account DROP COLUMN IF EXISTS OverdraftLimit;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS UserLimit (
+    UserID text PRIMARY KEY REFERENCES "User" (ID),
+    DailyLimit bigint NOT NULL CHECK (DailyLimit >= 0),
+    MonthlyLimit bigint NOT NULL CHECK (MonthlyLimit >= 0),
+    Currency text NOT NULL,
+    Updated timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS LimitUsage (
+    UserID text NOT NULL REFERENCES "User" (ID),
+    Day date NOT NULL,
+    Currency text NOT NULL,
+    Amount bigint NOT NULL CHECK (Amount >= 0),
+    PRIMARY KEY (UserID, Day, Currency)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS LimitUsage;
+DROP TABLE IF EXISTS UserLimit;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS AccountHolder (
+    AccountNumber text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    UserID text NOT NULL REFERENCES "User" (ID),
+    Role text NOT NULL CHECK (Role IN ('owner', 'signatory', 'viewer')),
+    AddedBy text NOT NULL,
+    Created timestamp NOT NULL,
+    PRIMARY KEY (AccountNumber, UserID)
+);
+
+CREATE INDEX IF NOT EXISTS AccountHolder_User ON AccountHolder (UserID);
+
+-- Every existing customer account starts out with its owner as the only holder.
+INSERT INTO AccountHolder (AccountNumber, UserID, Role, AddedBy, Created)
+SELECT a.AccountNumber, a.OwnerID, 'owner', a.OwnerID, a.Created
+FROM Account a JOIN "User" u ON u.ID = a.OwnerID
+WHERE a.Type <> 'internal'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS AccountHolder;
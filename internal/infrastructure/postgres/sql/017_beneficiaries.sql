@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS Beneficiary (
+    ID bigserial PRIMARY KEY,
+    UserID text NOT NULL REFERENCES "User" (ID),
+    Nickname text NOT NULL,
+    AccountNumber text NOT NULL,
+    Reference text NOT NULL DEFAULT '',
+    External boolean NOT NULL,
+    Created timestamp NOT NULL,
+    Deleted timestamp
+);
+
+-- Deleted beneficiaries are kept for the record; saving one again starts a new cooling-off period.
+CREATE UNIQUE INDEX IF NOT EXISTS Beneficiary_Account ON Beneficiary (UserID, AccountNumber) WHERE Deleted IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS Beneficiary;
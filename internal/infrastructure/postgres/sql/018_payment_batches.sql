@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS PaymentBatch (
+    ID bigserial PRIMARY KEY,
+    AccountNumber text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    Format text NOT NULL CHECK (Format IN ('csv', 'pain.001')),
+    Status text NOT NULL CHECK (Status IN ('pending', 'executed')),
+    Total bigint NOT NULL,
+    Currency text NOT NULL,
+    ValidLines integer NOT NULL,
+    InvalidLines integer NOT NULL,
+    CreatedBy text NOT NULL,
+    Created timestamp NOT NULL,
+    Executed timestamp
+);
+
+CREATE INDEX IF NOT EXISTS PaymentBatch_Account ON PaymentBatch (AccountNumber, Created);
+
+-- Lines keep the account number as uploaded; it need not name an existing account.
+CREATE TABLE IF NOT EXISTS PaymentLine (
+    ID bigserial PRIMARY KEY,
+    BatchID bigint NOT NULL REFERENCES PaymentBatch (ID),
+    Line integer NOT NULL,
+    ToAccount text NOT NULL,
+    Name text NOT NULL DEFAULT '',
+    Amount bigint NOT NULL,
+    Currency text NOT NULL,
+    Reference text NOT NULL DEFAULT '',
+    Status text NOT NULL CHECK (Status IN ('valid', 'invalid', 'succeeded', 'failed')),
+    Error text NOT NULL DEFAULT '',
+    TransferID bigint REFERENCES Transfer (ID)
+);
+
+CREATE INDEX IF NOT EXISTS PaymentLine_Batch ON PaymentLine (BatchID, Line);
+
+-- +goose Down
+DROP TABLE IF EXISTS PaymentLine;
+DROP TABLE IF EXISTS PaymentBatch;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS ReconciliationRun (
+    ID bigserial PRIMARY KEY,
+    Status text NOT NULL CHECK (Status IN ('running', 'completed', 'failed')),
+    Accounts integer NOT NULL DEFAULT 0,
+    Error text NOT NULL DEFAULT '',
+    Started timestamp NOT NULL,
+    Finished timestamp
+);
+
+-- Account numbers are not references, so the report survives whatever happens to the account.
+CREATE TABLE IF NOT EXISTS BalanceMismatch (
+    RunID bigint NOT NULL REFERENCES ReconciliationRun (ID),
+    AccountNumber text NOT NULL,
+    Stored bigint NOT NULL,
+    Computed bigint NOT NULL,
+    Currency text NOT NULL,
+    Checked timestamp NOT NULL,
+    PRIMARY KEY (RunID, AccountNumber)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS BalanceMismatch;
+DROP TABLE IF EXISTS ReconciliationRun;
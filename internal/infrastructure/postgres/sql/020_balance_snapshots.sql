@@ -0,0 +1,32 @@
+-- +goose Up
+-- Balance of an account as implied by the entries booked before At.
+CREATE TABLE IF NOT EXISTS BalanceSnapshot (
+    AccountNumber text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    At timestamp NOT NULL,
+    Balance bigint NOT NULL,
+    Currency text NOT NULL,
+    Created timestamp NOT NULL,
+    PRIMARY KEY (AccountNumber, At)
+);
+
+-- Seed a snapshot at the end of every past month an account moved in, so lookups into the
+-- history before the daily snapshots replay at most a month of entries.
+INSERT INTO BalanceSnapshot (AccountNumber, At, Balance, Currency, Created)
+SELECT m.AccountNumber,
+       m.MonthEnd,
+       SUM(m.Net) OVER (PARTITION BY m.AccountNumber ORDER BY m.MonthEnd),
+       a.Currency,
+       now()
+FROM (
+    SELECT AccountNumber,
+           date_trunc('month', Created) + interval '1 month' AS MonthEnd,
+           SUM(CASE WHEN Direction = 'credit' THEN Amount ELSE -Amount END) AS Net
+    FROM LedgerEntry
+    GROUP BY AccountNumber, date_trunc('month', Created)
+) m
+JOIN Account a ON a.AccountNumber = m.AccountNumber
+WHERE m.MonthEnd <= date_trunc('month', now()::timestamp)
+ON CONFLICT (AccountNumber, At) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS BalanceSnapshot;
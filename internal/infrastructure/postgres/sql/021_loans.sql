@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS Loan (
+    ID bigserial PRIMARY KEY,
+    AccountNumber text NOT NULL UNIQUE REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    RepaymentAccount text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    OwnerID text NOT NULL,
+    Principal bigint NOT NULL CHECK (Principal > 0),
+    Currency text NOT NULL,
+    AnnualRate numeric(12, 8) NOT NULL CHECK (AnnualRate >= 0),
+    Term integer NOT NULL CHECK (Term > 0),
+    Frequency text NOT NULL CHECK (Frequency IN ('weekly', 'monthly', 'quarterly')),
+    Method text NOT NULL CHECK (Method IN ('annuity', 'linear')),
+    LateFee bigint NOT NULL DEFAULT 0,
+    Status text NOT NULL CHECK (Status IN ('active', 'repaid')),
+    DisbursementID bigint REFERENCES LedgerTransaction (ID),
+    Disbursed timestamp NOT NULL,
+    Created timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS Loan_RepaymentAccount ON Loan (RepaymentAccount);
+
+CREATE TABLE IF NOT EXISTS LoanInstalment (
+    ID bigserial PRIMARY KEY,
+    LoanID bigint NOT NULL REFERENCES Loan (ID),
+    Number integer NOT NULL,
+    Due date NOT NULL,
+    Principal bigint NOT NULL,
+    Interest bigint NOT NULL,
+    LateFee bigint NOT NULL DEFAULT 0,
+    Currency text NOT NULL,
+    Status text NOT NULL CHECK (Status IN ('scheduled', 'paid', 'overdue')),
+    Attempts integer NOT NULL DEFAULT 0,
+    NextAttempt timestamp NOT NULL,
+    TransactionID bigint REFERENCES LedgerTransaction (ID),
+    Paid timestamp,
+    UNIQUE (LoanID, Number)
+);
+
+CREATE INDEX IF NOT EXISTS LoanInstalment_Due ON LoanInstalment (NextAttempt) WHERE Status <> 'paid';
+
+-- +goose Down
+DROP TABLE IF EXISTS LoanInstalment;
+DROP TABLE IF EXISTS Loan;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS TermDeposit (
+    ID bigserial PRIMARY KEY,
+    AccountNumber text NOT NULL UNIQUE REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    LinkedAccount text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    OwnerID text NOT NULL,
+    Principal bigint NOT NULL CHECK (Principal > 0),
+    Currency text NOT NULL,
+    AnnualRate numeric(12, 8) NOT NULL CHECK (AnnualRate >= 0),
+    Term integer NOT NULL CHECK (Term > 0),
+    PenaltyRate numeric(12, 8) NOT NULL CHECK (PenaltyRate >= 0 AND PenaltyRate <= 1),
+    Rollover boolean NOT NULL DEFAULT false,
+    Status text NOT NULL CHECK (Status IN ('active', 'matured', 'withdrawn')),
+    Start date NOT NULL,
+    Maturity date NOT NULL,
+    Interest bigint NOT NULL,
+    Rollovers integer NOT NULL DEFAULT 0,
+    FundingID bigint REFERENCES LedgerTransaction (ID),
+    PayoutID bigint REFERENCES LedgerTransaction (ID),
+    Created timestamp NOT NULL,
+    Closed timestamp
+);
+
+CREATE INDEX IF NOT EXISTS TermDeposit_LinkedAccount ON TermDeposit (LinkedAccount);
+CREATE INDEX IF NOT EXISTS TermDeposit_Maturity ON TermDeposit (Maturity) WHERE Status = 'active';
+
+-- +goose Down
+DROP TABLE IF EXISTS TermDeposit;
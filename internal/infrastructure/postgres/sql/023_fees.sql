@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS FeeRule (
+    ID bigserial PRIMARY KEY,
+    AccountType text NOT NULL,
+    Operation text NOT NULL CHECK (Operation IN ('maintenance', 'transfer', 'fx')),
+    Currency text NOT NULL,
+    MinAmount bigint NOT NULL CHECK (MinAmount >= 0),
+    Fixed bigint NOT NULL CHECK (Fixed >= 0),
+    Rate numeric(12, 8) NOT NULL CHECK (Rate >= 0 AND Rate <= 1),
+    CreatedBy text NOT NULL,
+    Created timestamp NOT NULL,
+    Deleted timestamp
+);
+
+-- Each tier exists once; replacing a rule deletes the old one.
+CREATE UNIQUE INDEX IF NOT EXISTS FeeRule_Tier ON FeeRule (AccountType, Operation, Currency, MinAmount) WHERE Deleted IS NULL;
+
+CREATE TABLE IF NOT EXISTS Fee (
+    ID bigserial PRIMARY KEY,
+    AccountNumber text NOT NULL REFERENCES Account (AccountNumber) ON UPDATE CASCADE,
+    Operation text NOT NULL,
+    RuleID bigint REFERENCES FeeRule (ID),
+    Basis bigint NOT NULL,
+    Amount bigint NOT NULL CHECK (Amount >= 0),
+    Currency text NOT NULL,
+    TransferID bigint REFERENCES Transfer (ID),
+    Period date,
+    Status text NOT NULL CHECK (Status IN ('charged', 'waived')),
+    TransactionID bigint REFERENCES LedgerTransaction (ID),
+    WaivedBy text,
+    WaiverReason text,
+    WaiverTransactionID bigint REFERENCES LedgerTransaction (ID),
+    Waived timestamp,
+    Created timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS Fee_AccountNumber ON Fee (AccountNumber, Created);
+-- An account pays one maintenance fee per month.
+CREATE UNIQUE INDEX IF NOT EXISTS Fee_Maintenance ON Fee (AccountNumber, Period) WHERE Operation = 'maintenance';
+
+-- +goose Down
+DROP TABLE IF EXISTS Fee;
+DROP TABLE IF EXISTS FeeRule;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS ApprovalRequest (
+    ID bigserial PRIMARY KEY,
+    Method text NOT NULL,
+    Route text NOT NULL,
+    Path text NOT NULL,
+    Query text NOT NULL,
+    Body bytea NOT NULL,
+    Amount bigint,
+    Currency text,
+    Status text NOT NULL CHECK (Status IN ('pending', 'approved', 'rejected', 'expired')),
+    MakerID text NOT NULL,
+    CheckerID text,
+    Reason text,
+    ResponseStatus int,
+    Response bytea,
+    Expires timestamp NOT NULL,
+    Created timestamp NOT NULL,
+    Decided timestamp
+);
+
+CREATE INDEX IF NOT EXISTS ApprovalRequest_Status ON ApprovalRequest (Status, Expires);
+
+-- +goose Down
+DROP TABLE IF EXISTS ApprovalRequest;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- What a hold took off its owner's limits, so the part never captured can be given back.
+ALTER TABLE Hold ADD COLUMN IF NOT EXISTS LimitUserID text REFERENCES "User" (ID);
+ALTER TABLE Hold ADD COLUMN IF NOT EXISTS LimitUsed bigint NOT NULL DEFAULT 0 CHECK (LimitUsed >= 0);
+ALTER TABLE Hold ADD COLUMN IF NOT EXISTS LimitCurrency text;
+
+-- +goose Down
+ALTER TABLE Hold DROP COLUMN IF EXISTS LimitCurrency;
+ALTER TABLE Hold DROP COLUMN IF EXISTS LimitUsed;
+ALTER TABLE Hold DROP COLUMN IF EXISTS LimitUserID;
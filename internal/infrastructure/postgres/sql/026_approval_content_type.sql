@@ -0,0 +1,6 @@
+-- +goose Up
+-- The media type of the held-back body, so uploads are replayed as they were sent.
+ALTER TABLE ApprovalRequest ADD COLUMN IF NOT EXISTS ContentType text NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE ApprovalRequest DROP COLUMN IF EXISTS ContentType;
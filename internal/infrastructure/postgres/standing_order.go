@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"strconv"
+	"time"
+)
+
+const standingOrderColumns = `ID, OwnerID, FromAccount, ToAccount, Amount, Currency, Reference, Frequency, DayOfMonth,
+                              StartDate, EndDate, NextRun, NextAttempt, Attempts, Status, Created`
+
+func scanStandingOrder(row rowScanner) (*StandingOrder, error) {
+	order := &StandingOrder{}
+	var endDate sql.NullTime
+	err := row.Scan(
+		&order.ID,
+		&order.OwnerID,
+		&order.FromAccount,
+		&order.ToAccount,
+		&order.Amount.Amount,
+		&order.Amount.Currency,
+		&order.Reference,
+		&order.Frequency,
+		&order.DayOfMonth,
+		&order.StartDate,
+		&endDate,
+		&order.NextRun,
+		&order.NextAttempt,
+		&order.Attempts,
+		&order.Status,
+		&order.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Valid {
+		order.EndDate = &endDate.Time
+	}
+	return order, nil
+}
+
+func (s *PostgresqlStore) CreateStandingOrder(order *StandingOrder) error {
+	return s.db.QueryRow(
+		`INSERT INTO StandingOrder (OwnerID, FromAccount, ToAccount, Amount, Currency, Reference, Frequency, DayOfMonth,
+                                    StartDate, EndDate, NextRun, NextAttempt, Attempts, Status, Created)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+         RETURNING ID`,
+		order.OwnerID,
+		order.FromAccount,
+		order.ToAccount,
+		order.Amount.Amount,
+		order.Amount.Currency,
+		order.Reference,
+		order.Frequency,
+		order.DayOfMonth,
+		order.StartDate,
+		order.EndDate,
+		order.NextRun,
+		order.NextAttempt,
+		order.Attempts,
+		order.Status,
+		order.Created,
+	).Scan(&order.ID)
+}
+
+func (s *PostgresqlStore) GetStandingOrder(id int64) (*StandingOrder, error) {
+	order, err := scanStandingOrder(s.db.QueryRow(`SELECT `+standingOrderColumns+` FROM StandingOrder WHERE ID = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *PostgresqlStore) GetStandingOrders(ownerID string) ([]*StandingOrder, error) {
+	rows, err := s.db.Query(`SELECT `+standingOrderColumns+` FROM StandingOrder WHERE OwnerID = $1 ORDER BY ID`, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*StandingOrder
+	for rows.Next() {
+		order, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func (s *PostgresqlStore) CancelStandingOrder(id int64, actorID string) (*StandingOrder, error) {
+	var order *StandingOrder
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		order, err = scanStandingOrder(tx.QueryRow(`SELECT `+standingOrderColumns+` FROM StandingOrder WHERE ID = $1 FOR UPDATE`, id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrStandingOrderNotFound, "%d", id)
+			}
+			return err
+		}
+		if err := order.Cancel(); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE StandingOrder SET Status = $1 WHERE ID = $2`, order.Status, id); err != nil {
+			return err
+		}
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "cancel", "standing_order", strconv.FormatInt(id, 10), ""))
+	})
+	return order, err
+}
+
+// GetDueStandingOrders returns the IDs of up to limit active orders whose next attempt is due.
+func (s *PostgresqlStore) GetDueStandingOrders(now time.Time, limit int) ([]int64, error) {
+	rows, err := s.db.Query(
+		`SELECT ID FROM StandingOrder WHERE Status = $1 AND NextAttempt <= $2 ORDER BY NextAttempt, ID LIMIT $3`,
+		ActiveStandingOrder,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ExecuteStandingOrder pays the order if it is still due, recording the outcome and moving the
+// order on in the same database transaction as the transfer, so every due payment is made at most
+// once. Payments rejected by the bank (e.g. for insufficient funds) are retried according to
+// policy. It returns nil when the order was not due or is being executed elsewhere.
+func (s *PostgresqlStore) ExecuteStandingOrder(id int64, now time.Time, policy RetryPolicy) (*StandingOrderExecution, error) {
+	var execution *StandingOrderExecution
+	err := s.withTx(func(tx *sql.Tx) error {
+		order, err := scanStandingOrder(tx.QueryRow(
+			`SELECT `+standingOrderColumns+` FROM StandingOrder
+             WHERE ID = $1 AND Status = $2 AND NextAttempt <= $3
+             FOR UPDATE SKIP LOCKED`,
+			id,
+			ActiveStandingOrder,
+			now,
+		))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		execution = &StandingOrderExecution{
+			OrderID: order.ID,
+			Due:     order.NextRun,
+			Attempt: order.Attempts + 1,
+			Created: now,
+		}
+		if _, err := tx.Exec(`SAVEPOINT payment`); err != nil {
+			return err
+		}
+		// The owner of the order may have lost access to the account since it was set up.
+		var transfer *Transfer
+		err = checkCanMoveMoney(tx, order.FromAccount, order.OwnerID)
+		if err == nil {
+			transfer, err = NewTransfer(order.FromAccount, order.ToAccount, order.Amount, order.Reference, order.OwnerID)
+		}
+		if err == nil {
+			err = s.createTransfer(tx, transfer)
+		}
+		switch {
+		case err == nil:
+			execution.Status = ExecutionSucceeded
+			execution.TransferID = transfer.ID
+			order.Advance()
+		case isPaymentRejection(err):
+			if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT payment`); err != nil {
+				return err
+			}
+			execution.Status = order.RecordFailure(now, policy)
+			execution.Error = err.Error()
+		default:
+			return err
+		}
+
+		err = tx.QueryRow(
+			`INSERT INTO StandingOrderExecution (OrderID, Due, Attempt, Status, TransferID, Error, Created)
+             VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, $7)
+             RETURNING ID`,
+			execution.OrderID,
+			execution.Due,
+			execution.Attempt,
+			execution.Status,
+			execution.TransferID,
+			execution.Error,
+			execution.Created,
+		).Scan(&execution.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`UPDATE StandingOrder SET NextRun = $1, NextAttempt = $2, Attempts = $3, Status = $4 WHERE ID = $5`,
+			order.NextRun,
+			order.NextAttempt,
+			order.Attempts,
+			order.Status,
+			order.ID,
+		)
+		return err
+	})
+	return execution, err
+}
+
+// isPaymentRejection tells the errors the bank rejects a payment with apart from technical failures.
+func isPaymentRejection(err error) bool {
+	for _, rejection := range []error{
+		ErrInsufficientFunds,
+		ErrAccountNotActive,
+		ErrAccountNotFound,
+		ErrCurrencyMismatch,
+		ErrNoExchangeRate,
+		ErrInvalidAmount,
+		ErrSameAccount,
+		ErrLimitExceeded,
+		ErrHolderNotAllowed,
+		ErrLoanAccount,
+		ErrTermDepositLocked,
+	} {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PostgresqlStore) GetStandingOrderExecutions(orderID int64) ([]*StandingOrderExecution, error) {
+	rows, err := s.db.Query(
+		`SELECT ID, OrderID, Due, Attempt, Status, COALESCE(TransferID, 0), Error, Created
+         FROM StandingOrderExecution WHERE OrderID = $1 ORDER BY ID`,
+		orderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var executions []*StandingOrderExecution
+	for rows.Next() {
+		e := &StandingOrderExecution{}
+		err := rows.Scan(&e.ID, &e.OrderID, &e.Due, &e.Attempt, &e.Status, &e.TransferID, &e.Error, &e.Created)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, e)
+	}
+	return executions, rows.Err()
+}
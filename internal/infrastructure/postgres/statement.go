@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"time"
+)
+
+// GetStatement reads the movements booked on an account in [from, to) together with the balance
+// at the start of the period. Both are read from one snapshot so they always agree.
+func (s *PostgresqlStore) GetStatement(accountNumber string, from time.Time, to time.Time) (*Statement, error) {
+	tx, err := s.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	account, err := scanAccount(tx.QueryRow(`SELECT `+accountColumns+` FROM Account WHERE AccountNumber = $1`, accountNumber))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	opening, err := balanceAt(tx, accountNumber, account.Balance.Currency, from)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := getMovements(tx, accountNumber, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return NewStatement(account, s.accountPrefix, from, to, opening, movements)
+}
+
+func getMovements(q querier, accountNumber string, from time.Time, to time.Time) ([]Movement, error) {
+	rows, err := q.Query(
+		`SELECT e.ID, e.TransactionID, t.Type, t.Reference, t.Description, COALESCE(t.ReversalOf, 0), e.Direction, e.Amount, e.Currency, e.Created
+         FROM LedgerEntry e JOIN LedgerTransaction t ON t.ID = e.TransactionID
+         WHERE e.AccountNumber = $1 AND e.Created >= $2 AND e.Created < $3
+         ORDER BY e.Created, e.ID`,
+		accountNumber,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []Movement
+	for rows.Next() {
+		var m Movement
+		var direction EntryDirection
+		err := rows.Scan(
+			&m.EntryID,
+			&m.TransactionID,
+			&m.Type,
+			&m.Reference,
+			&m.Description,
+			&m.ReversalOf,
+			&direction,
+			&m.Amount.Amount,
+			&m.Amount.Currency,
+			&m.Booked,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if direction == Debit {
+			m.Amount = m.Amount.Neg()
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
@@ -4,24 +4,117 @@ import (
 	"database/sql"
 	"fmt"
 	_ "github.com/lib/pq"
+	"github.com/pkg/errors"
 	. "go-bank-v2/internal/types"
+	"time"
 )
 
 type Store interface {
 	CreateAccount(*Account) error
-	DeleteAccount(int) error
-	UpdateAccountBalance(*Account, float64) error
-	GetAccounts(int) ([]*Account, error)
-	GetAccountByNumber(int) (*Account, error)
-	GetAllAccounts() ([]*Account, error)
+	AdjustAccountBalance(*Account, Money, string, string) (*Transaction, error)
+	GetAccounts(string, ...AccountStatus) ([]*Account, error)
+	GetAccountByNumber(string) (*Account, error)
+	GetAllAccounts(...AccountStatus) ([]*Account, error)
+	UpdateAccountStatus(string, AccountStatus, string, string) error
+	CloseAccount(string, string, string, string) (*Transfer, error)
+	SetOverdraftLimit(string, Money, string) (*Account, error)
+	GetAccountHolder(string, string) (*AccountHolder, error)
+	GetAccountHolders(string) ([]*AccountHolder, error)
+	SaveAccountHolder(*AccountHolder, string) error
+	RemoveAccountHolder(string, string, string) error
 	CreateUser(*User) error
-	DeleteUser(int) error
-	GetUserByID(int) (*User, error)
+	DeactivateUser(string, string) error
+	GetUserByID(string) (*User, error)
+	PostTransaction(*Transaction) error
+	GetTransaction(int64) (*Transaction, error)
+	ReverseTransaction(int64, *Money, string, string) (*Transaction, error)
+	GetReversals(int64) ([]*Transaction, error)
+	GetLedgerEntries(string) ([]*LedgerEntry, error)
+	GetStatement(string, time.Time, time.Time) (*Statement, error)
+	GetSystemAccount(SystemAccountCode, Currency) (*Account, error)
+	CreateTransfer(*Transfer) error
+	GetTransfer(int64) (*Transfer, error)
+	RecordAuditEvent(*AuditEvent) error
+	GetAuditEvents(string, string) ([]*AuditEvent, error)
+	ClaimIdempotencyKey(*IdempotencyRecord) (*IdempotencyRecord, error)
+	CompleteIdempotencyKey(*IdempotencyRecord) error
+	ReleaseIdempotencyKey(string, string) error
+	PurgeIdempotencyKeys(time.Time, int) (int, error)
+	SaveExchangeRates([]*ExchangeRate) error
+	GetExchangeRates() ([]*ExchangeRate, error)
+	GetExchangeRate(Currency, Currency, time.Time) (*ExchangeRate, error)
+	GetBalanceAt(string, time.Time) (*Money, error)
+	SnapshotBalances(time.Time, int) (int, error)
+	SaveInterestRate(*InterestRate, string) error
+	GetInterestRate(string, time.Time) (*InterestRate, error)
+	SaveInterestAccrual(*InterestAccrual) (bool, error)
+	GetLastInterestAccrual(string) (*InterestAccrual, error)
+	GetInterestAccruals(string, time.Time, time.Time) ([]*InterestAccrual, error)
+	CapitaliseInterest(string, time.Time) (*Transaction, error)
+	CreateStandingOrder(*StandingOrder) error
+	GetStandingOrder(int64) (*StandingOrder, error)
+	GetStandingOrders(string) ([]*StandingOrder, error)
+	CancelStandingOrder(int64, string) (*StandingOrder, error)
+	GetDueStandingOrders(time.Time, int) ([]int64, error)
+	ExecuteStandingOrder(int64, time.Time, RetryPolicy) (*StandingOrderExecution, error)
+	GetStandingOrderExecutions(int64) ([]*StandingOrderExecution, error)
+	CreateHold(*Hold) error
+	GetHold(int64) (*Hold, error)
+	GetHolds(string, ...HoldStatus) ([]*Hold, error)
+	CaptureHold(int64, Money) (*Hold, *Transaction, error)
+	ReleaseHold(int64) (*Hold, error)
+	ExpireHolds(time.Time, int) (int, error)
+	GetLimits(string, time.Time) (*Limits, error)
+	CreateBeneficiary(*Beneficiary) error
+	GetBeneficiary(int64) (*Beneficiary, error)
+	LastSavedBeneficiary(string, string) (*Beneficiary, error)
+	GetBeneficiaries(string) ([]*Beneficiary, error)
+	DeleteBeneficiary(int64, string) error
+	CreateLoan(*Loan, string) error
+	GetLoan(int64) (*Loan, error)
+	GetLoans(string) ([]*Loan, error)
+	GetLoanInstalments(int64) ([]*LoanInstalment, error)
+	GetDueLoanInstalments(time.Time, int) ([]int64, error)
+	CollectLoanInstalment(int64, time.Time, time.Duration) (*LoanInstalment, error)
+	CreateTermDeposit(*TermDeposit, string) error
+	GetTermDeposit(int64) (*TermDeposit, error)
+	GetTermDeposits(string) ([]*TermDeposit, error)
+	GetMaturedTermDeposits(time.Time, int) ([]int64, error)
+	MatureTermDeposit(int64, time.Time) (*TermDeposit, error)
+	WithdrawTermDeposit(int64, string) (*TermDeposit, error)
+	SetTermDepositRollover(int64, bool, string) (*TermDeposit, error)
+	SetFeeRule(*FeeRule) error
+	DeleteFeeRule(int64, string) error
+	GetFeeSchedule() ([]*FeeRule, error)
+	GetMaintenanceFeeAccounts(time.Time, string, int) ([]string, error)
+	ChargeMaintenanceFee(string, time.Time) (*Fee, error)
+	GetFee(int64) (*Fee, error)
+	GetFees(string, time.Time, time.Time) ([]*Fee, error)
+	WaiveFee(int64, string, string) (*Fee, error)
+	CreatePaymentBatch(*PaymentBatch) error
+	GetPaymentBatch(int64) (*PaymentBatch, error)
+	GetPaymentBatches(string) ([]*PaymentBatch, error)
+	ExecutePaymentBatch(int64, string) (*PaymentBatch, error)
+	CreateReconciliationRun(*ReconciliationRun) error
+	ReconcileBalances(*ReconciliationRun, []string) ([]*BalanceMismatch, error)
+	FinishReconciliationRun(*ReconciliationRun) error
+	GetReconciliationRun(int64) (*ReconciliationRun, error)
+	SetUserLimits(*UserLimits, string) error
+	ResetUserLimits(string, string) error
+	CreateApprovalRequest(*ApprovalRequest) error
+	GetApprovalRequest(int64) (*ApprovalRequest, error)
+	GetApprovalRequests(...ApprovalStatus) ([]*ApprovalRequest, error)
+	ApproveRequest(int64, string) (*ApprovalRequest, error)
+	RejectRequest(int64, string, string) (*ApprovalRequest, error)
+	CompleteApprovalRequest(*ApprovalRequest) error
+	ExpireApprovalRequests(time.Time, int) (int, error)
 	Migrate() error
 }
 
 type PostgresqlStore struct {
-	db *sql.DB
+	db            *sql.DB
+	accountPrefix string
+	defaultLimits UserLimits
 }
 
 func NewPostgresStore(config DbConfig) (*PostgresqlStore, error) {
@@ -35,11 +128,42 @@ func NewPostgresStore(config DbConfig) (*PostgresqlStore, error) {
 		return nil, err
 	}
 
+	if _, err := FormatAccountNumber(config.AccountPrefix, 1); err != nil {
+		return nil, err
+	}
+	defaultLimits, err := parseDefaultLimits(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PostgresqlStore{
-		db: db,
+		db:            db,
+		accountPrefix: config.AccountPrefix,
+		defaultLimits: *defaultLimits,
 	}, nil
 }
 
+func parseDefaultLimits(config DbConfig) (*UserLimits, error) {
+	currency, err := ParseCurrency(config.LimitCurrency)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := ParseMoney(config.DailyLimit, currency)
+	if err != nil {
+		return nil, errors.Wrap(err, "LIMITS_DAILY")
+	}
+	monthly, err := ParseMoney(config.MonthlyLimit, currency)
+	if err != nil {
+		return nil, errors.Wrap(err, "LIMITS_MONTHLY")
+	}
+	limits, err := NewUserLimits("", daily, monthly)
+	if err != nil {
+		return nil, err
+	}
+	limits.Custom = false
+	return limits, nil
+}
+
 func connectionString(config DbConfig) string {
 	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
@@ -52,7 +176,7 @@ func connectionString(config DbConfig) string {
 }
 
 func (s *PostgresqlStore) Migrate() error {
-	m := NewMigrator()
+	m := NewMigrator(s.accountPrefix)
 
 	err := m.Migrate(s.db)
 	if err != nil {
@@ -61,6 +185,19 @@ func (s *PostgresqlStore) Migrate() error {
 	return nil
 }
 
+// withTx runs fn inside a database transaction, committing on success and rolling back on error.
+func (s *PostgresqlStore) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 func (s *PostgresqlStore) close() {
 	err := s.db.Close()
 	if err != nil {
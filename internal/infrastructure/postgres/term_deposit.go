@@ -0,0 +1,312 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"strconv"
+	"time"
+)
+
+const termDepositColumns = `ID, AccountNumber, LinkedAccount, OwnerID, Principal, Currency, AnnualRate, Term, PenaltyRate, Rollover,
+                            Status, Start, Maturity, Interest, Rollovers, COALESCE(FundingID, 0), COALESCE(PayoutID, 0), Created, Closed`
+
+func scanTermDeposit(row rowScanner) (*TermDeposit, error) {
+	d := &TermDeposit{}
+	var closed sql.NullTime
+	err := row.Scan(
+		&d.ID,
+		&d.AccountNumber,
+		&d.LinkedAccount,
+		&d.OwnerID,
+		&d.Principal.Amount,
+		&d.Principal.Currency,
+		&d.AnnualRate,
+		&d.Term,
+		&d.PenaltyRate,
+		&d.Rollover,
+		&d.Status,
+		&d.Start,
+		&d.Maturity,
+		&d.Interest.Amount,
+		&d.Rollovers,
+		&d.FundingID,
+		&d.PayoutID,
+		&d.Created,
+		&closed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Interest.Currency = d.Principal.Currency
+	if closed.Valid {
+		d.Closed = &closed.Time
+	}
+	return d, nil
+}
+
+// CreateTermDeposit opens a term deposit account for the owner, funds it from the linked account
+// and stores the deposit, all in one database transaction.
+func (s *PostgresqlStore) CreateTermDeposit(d *TermDeposit, actorID string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		locked, err := lockAccounts(tx, []string{d.LinkedAccount})
+		if err != nil {
+			return err
+		}
+		linked, ok := locked[d.LinkedAccount]
+		if !ok || !linked.Payable() {
+			return errors.Wrapf(ErrAccountNotFound, "%s", d.LinkedAccount)
+		}
+		if err := linked.CheckCanPost(); err != nil {
+			return err
+		}
+		if linked.Balance.Currency != d.Principal.Currency {
+			return errors.Wrapf(ErrCurrencyMismatch, "account %s is held in %s", linked.AccountNumber, linked.Balance.Currency)
+		}
+
+		account := NewAccount(d.OwnerID, d.Principal.Currency)
+		account.Type = TermDepositAccount
+		if err := s.insertAccount(tx, account); err != nil {
+			return err
+		}
+		d.AccountNumber = account.AccountNumber
+
+		funding := NewTermDepositFunding(d)
+		if err := s.postTransaction(tx, funding); err != nil {
+			return err
+		}
+		d.FundingID = funding.ID
+
+		err = tx.QueryRow(
+			`INSERT INTO TermDeposit (AccountNumber, LinkedAccount, OwnerID, Principal, Currency, AnnualRate, Term, PenaltyRate,
+                                      Rollover, Status, Start, Maturity, Interest, FundingID, Created)
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+             RETURNING ID`,
+			d.AccountNumber,
+			d.LinkedAccount,
+			d.OwnerID,
+			d.Principal.Amount,
+			d.Principal.Currency,
+			d.AnnualRate,
+			d.Term,
+			d.PenaltyRate,
+			d.Rollover,
+			d.Status,
+			d.Start,
+			d.Maturity,
+			d.Interest.Amount,
+			d.FundingID,
+			d.Created,
+		).Scan(&d.ID)
+		if err != nil {
+			return err
+		}
+
+		details := d.Principal.Format() + " from " + d.LinkedAccount + " until " + d.Maturity.Format("2006-01-02")
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "create_term_deposit", "account", d.AccountNumber, details))
+	})
+}
+
+// GetTermDeposit returns the term deposit or nil.
+func (s *PostgresqlStore) GetTermDeposit(id int64) (*TermDeposit, error) {
+	d, err := getTermDeposit(s.db, id, false)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func getTermDeposit(q querier, id int64, forUpdate bool) (*TermDeposit, error) {
+	query := `SELECT ` + termDepositColumns + ` FROM TermDeposit WHERE ID = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+	return scanTermDeposit(q.QueryRow(query, id))
+}
+
+// GetTermDeposits lists the term deposits funded from an account, newest first.
+func (s *PostgresqlStore) GetTermDeposits(linkedAccount string) ([]*TermDeposit, error) {
+	rows, err := s.db.Query(
+		`SELECT `+termDepositColumns+` FROM TermDeposit WHERE LinkedAccount = $1 ORDER BY Created DESC, ID DESC`,
+		linkedAccount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deposits []*TermDeposit
+	for rows.Next() {
+		d, err := scanTermDeposit(rows)
+		if err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, d)
+	}
+	return deposits, rows.Err()
+}
+
+// GetMaturedTermDeposits returns up to limit active deposits whose maturity date has been reached.
+func (s *PostgresqlStore) GetMaturedTermDeposits(now time.Time, limit int) ([]int64, error) {
+	rows, err := s.db.Query(
+		`SELECT ID FROM TermDeposit WHERE Status = $1 AND Maturity <= $2 ORDER BY Maturity, ID LIMIT $3`,
+		ActiveTermDeposit,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// MatureTermDeposit pays the interest of a deposit that reached its maturity date. Deposits set to
+// roll over start their next term with the interest added; the others are paid back to the linked
+// account and their deposit account is closed. It returns nil when the deposit has not matured or
+// is being processed elsewhere.
+func (s *PostgresqlStore) MatureTermDeposit(id int64, now time.Time) (*TermDeposit, error) {
+	var deposit *TermDeposit
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		deposit, err = scanTermDeposit(tx.QueryRow(
+			`SELECT `+termDepositColumns+` FROM TermDeposit
+             WHERE ID = $1 AND Status = $2 AND Maturity <= $3
+             FOR UPDATE SKIP LOCKED`,
+			id,
+			ActiveTermDeposit,
+			now,
+		))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				deposit = nil
+				return nil
+			}
+			return err
+		}
+		expense, err := s.getSystemAccount(tx, InterestExpenseSystemAccount, deposit.Principal.Currency)
+		if err != nil {
+			return err
+		}
+
+		var payoutID int64
+		if maturity := NewTermDepositMaturity(deposit, expense.AccountNumber); maturity != nil {
+			if err := s.postTransaction(tx, maturity); err != nil {
+				return err
+			}
+			payoutID = maturity.ID
+		}
+
+		if deposit.Rollover {
+			if err := deposit.RollOver(); err != nil {
+				return err
+			}
+			_, err := tx.Exec(
+				`UPDATE TermDeposit SET Principal = $1, Interest = $2, Start = $3, Maturity = $4, Rollovers = $5 WHERE ID = $6`,
+				deposit.Principal.Amount,
+				deposit.Interest.Amount,
+				deposit.Start,
+				deposit.Maturity,
+				deposit.Rollovers,
+				deposit.ID,
+			)
+			return err
+		}
+
+		deposit.Close(MaturedTermDeposit, now, payoutID)
+		return closeTermDeposit(tx, deposit, "Term deposit matured", SystemActor)
+	})
+	return deposit, err
+}
+
+// WithdrawTermDeposit pays an active deposit back to its linked account before maturity. The
+// interest of the running term is forfeited and the penalty kept by the bank.
+func (s *PostgresqlStore) WithdrawTermDeposit(id int64, actorID string) (*TermDeposit, error) {
+	var deposit *TermDeposit
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		deposit, err = getTermDeposit(tx, id, true)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrTermDepositNotFound, "%d", id)
+			}
+			return err
+		}
+		if err := deposit.CheckActive(); err != nil {
+			return err
+		}
+		now := time.Now()
+		if !now.Before(deposit.Maturity) {
+			return errors.Wrapf(ErrTermDepositNotActive, "term deposit %d has matured and is being paid out", id)
+		}
+
+		feeIncome, err := s.getSystemAccount(tx, FeeIncomeSystemAccount, deposit.Principal.Currency)
+		if err != nil {
+			return err
+		}
+		withdrawal, err := NewTermDepositWithdrawal(deposit, feeIncome.AccountNumber)
+		if err != nil {
+			return err
+		}
+		if err := s.postTransaction(tx, withdrawal); err != nil {
+			return err
+		}
+
+		deposit.Close(WithdrawnTermDeposit, now, withdrawal.ID)
+		return closeTermDeposit(tx, deposit, "Term deposit withdrawn early", actorID)
+	})
+	return deposit, err
+}
+
+// closeTermDeposit stores the outcome of a deposit that has been paid back and closes its account,
+// which is empty by now.
+func closeTermDeposit(tx *sql.Tx, d *TermDeposit, reason string, actorID string) error {
+	_, err := tx.Exec(
+		`UPDATE TermDeposit SET Status = $1, PayoutID = NULLIF($2, 0), Closed = $3 WHERE ID = $4`,
+		d.Status,
+		d.PayoutID,
+		d.Closed,
+		d.ID,
+	)
+	if err != nil {
+		return err
+	}
+	return updateAccountStatus(tx, d.AccountNumber, ClosedAccount, reason, actorID)
+}
+
+// SetTermDepositRollover decides whether an active deposit rolls over at maturity or is paid back.
+func (s *PostgresqlStore) SetTermDepositRollover(id int64, rollover bool, actorID string) (*TermDeposit, error) {
+	var deposit *TermDeposit
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		deposit, err = getTermDeposit(tx, id, true)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.Wrapf(ErrTermDepositNotFound, "%d", id)
+			}
+			return err
+		}
+		if err := deposit.CheckActive(); err != nil {
+			return err
+		}
+		deposit.Rollover = rollover
+		if _, err := tx.Exec(`UPDATE TermDeposit SET Rollover = $1 WHERE ID = $2`, rollover, id); err != nil {
+			return err
+		}
+		details := "rollover " + strconv.FormatBool(rollover)
+		return recordAuditEvent(tx, NewAuditEvent(actorID, "set_term_deposit_rollover", "account", deposit.AccountNumber, details))
+	})
+	return deposit, err
+}
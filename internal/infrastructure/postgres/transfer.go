@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+)
+
+// CreateTransfer books the transfer and stores its record in one database transaction.
+// Both accounts stay locked until the transfer is committed or rolled back.
+func (s *PostgresqlStore) CreateTransfer(transfer *Transfer) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.createTransfer(tx, transfer)
+	})
+}
+
+// createTransfer books the transfer and charges its fees to the source account. A transfer the
+// account can't cover together with its fees is rejected as a whole.
+func (s *PostgresqlStore) createTransfer(tx *sql.Tx, transfer *Transfer) error {
+	if err := s.bookTransfer(tx, transfer); err != nil {
+		return err
+	}
+	return s.chargeTransferFees(tx, transfer)
+}
+
+// bookTransfer converts the amount when the destination account is held in another currency,
+// using the exchange rate valid at booking time. No fees are charged.
+func (s *PostgresqlStore) bookTransfer(tx *sql.Tx, transfer *Transfer) error {
+	var destinationCurrency Currency
+	var destinationType AccountType
+	err := tx.QueryRow(`SELECT Currency, Type FROM Account WHERE AccountNumber = $1`, transfer.ToAccount).Scan(&destinationCurrency, &destinationType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrapf(ErrAccountNotFound, "%s", transfer.ToAccount)
+		}
+		return err
+	}
+	switch destinationType {
+	case LoanAccount:
+		return errors.Wrapf(ErrLoanAccount, "%s", transfer.ToAccount)
+	case TermDepositAccount:
+		return errors.Wrapf(ErrTermDepositLocked, "%s", transfer.ToAccount)
+	}
+
+	var fxSource, fxDestination string
+	if destinationCurrency != transfer.Amount.Currency {
+		rate, err := getExchangeRate(tx, transfer.Amount.Currency, destinationCurrency, transfer.Created)
+		if err != nil {
+			return err
+		}
+		if err := transfer.Convert(rate); err != nil {
+			return err
+		}
+		source, err := s.getSystemAccount(tx, FxPositionSystemAccount, transfer.Amount.Currency)
+		if err != nil {
+			return err
+		}
+		destination, err := s.getSystemAccount(tx, FxPositionSystemAccount, destinationCurrency)
+		if err != nil {
+			return err
+		}
+		fxSource, fxDestination = source.AccountNumber, destination.AccountNumber
+	}
+
+	txn := transfer.Transaction(fxSource, fxDestination)
+	if err := s.postTransaction(tx, txn); err != nil {
+		return err
+	}
+	transfer.TransactionID = txn.ID
+
+	return tx.QueryRow(
+		`INSERT INTO Transfer (FromAccount, ToAccount, Amount, Currency, ConvertedAmount, ConvertedCurrency, ExchangeRate,
+                               Reference, TransactionID, InitiatedBy, Created)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+         RETURNING ID`,
+		transfer.FromAccount,
+		transfer.ToAccount,
+		transfer.Amount.Amount,
+		transfer.Amount.Currency,
+		transfer.ConvertedAmount.Amount,
+		transfer.ConvertedAmount.Currency,
+		transfer.ExchangeRate,
+		transfer.Reference,
+		transfer.TransactionID,
+		transfer.InitiatedBy,
+		transfer.Created,
+	).Scan(&transfer.ID)
+}
+
+func (s *PostgresqlStore) GetTransfer(id int64) (*Transfer, error) {
+	transfer, err := scanTransfer(s.db.QueryRow(`SELECT `+transferColumns+` FROM Transfer WHERE ID = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return transfer, nil
+}
+
+const transferColumns = `ID, FromAccount, ToAccount, Amount, Currency, ConvertedAmount, ConvertedCurrency, ExchangeRate,
+                         Reference, TransactionID, InitiatedBy, Created`
+
+func scanTransfer(row rowScanner) (*Transfer, error) {
+	transfer := &Transfer{}
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.FromAccount,
+		&transfer.ToAccount,
+		&transfer.Amount.Amount,
+		&transfer.Amount.Currency,
+		&transfer.ConvertedAmount.Amount,
+		&transfer.ConvertedAmount.Currency,
+		&transfer.ExchangeRate,
+		&transfer.Reference,
+		&transfer.TransactionID,
+		&transfer.InitiatedBy,
+		&transfer.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
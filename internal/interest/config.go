@@ -0,0 +1,14 @@
+package interest
+
+import "time"
+
+type Config struct {
+	// DayCount is the convention daily accruals are computed with: ACT/365, ACT/360, ACT/ACT or 30/360.
+	DayCount string `env:"INTEREST_DAY_COUNT" envDefault:"ACT/365"`
+	// SavingsRate is the annual rate of savings accounts that have no rate of their own.
+	SavingsRate string `env:"INTEREST_SAVINGS_RATE" envDefault:"0"`
+	// OverdraftRate is the annual rate charged on negative balances; zero charges nothing.
+	OverdraftRate string `env:"INTEREST_OVERDRAFT_RATE" envDefault:"0"`
+	// Interval between in-process runs; zero disables them.
+	Interval time.Duration `env:"INTEREST_RUN_INTERVAL" envDefault:"1h"`
+}
@@ -0,0 +1,164 @@
+// Package interest accrues interest on customer accounts daily and capitalises it monthly.
+package interest
+
+import (
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/infrastructure/postgres"
+	. "go-bank-v2/internal/types"
+	"log"
+	"time"
+)
+
+type Engine struct {
+	store         Store
+	dayCount      DayCount
+	savingsRate   string
+	overdraftRate string
+	interval      time.Duration
+}
+
+func NewEngine(config Config, store Store) (*Engine, error) {
+	dayCount, err := ParseDayCount(config.DayCount)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ParseInterestRate(config.SavingsRate); err != nil {
+		return nil, err
+	}
+	overdraftRate, err := ParseInterestRate(config.OverdraftRate)
+	if err != nil {
+		return nil, err
+	}
+	engine := &Engine{
+		store:       store,
+		dayCount:    dayCount,
+		savingsRate: config.SavingsRate,
+		interval:    config.Interval,
+	}
+	if overdraftRate.Sign() > 0 {
+		engine.overdraftRate = config.OverdraftRate
+	}
+	return engine, nil
+}
+
+// Start runs the engine in the background every configured interval.
+func (e *Engine) Start() {
+	if e.interval <= 0 {
+		return
+	}
+	go func() {
+		for {
+			if err := e.Run(time.Now()); err != nil {
+				log.Printf("interest: %v", err)
+			}
+			time.Sleep(e.interval)
+		}
+	}()
+}
+
+// Run accrues interest for every day before now that has not been accrued yet and capitalises
+// the accruals of completed months on active accounts. Days and months already processed are
+// skipped, so Run may be repeated at will. A failing account does not stop the others.
+func (e *Engine) Run(now time.Time) error {
+	accounts, err := e.store.GetAllAccounts(ActiveAccount, FrozenAccount, DormantAccount)
+	if err != nil {
+		return err
+	}
+
+	today := Day(now)
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	failed := 0
+	for _, account := range accounts {
+		// Term deposits earn their fixed interest at maturity instead.
+		if !account.Payable() {
+			continue
+		}
+		if err := e.accrue(account, today); err != nil {
+			log.Printf("interest: accruing %s: %v", account.AccountNumber, err)
+			failed++
+			continue
+		}
+		// Nothing may be posted to frozen or dormant accounts; their accruals stay pending and are
+		// capitalised once the account is active again.
+		if account.CheckCanPost() != nil {
+			continue
+		}
+		if _, err := e.store.CapitaliseInterest(account.AccountNumber, monthStart); err != nil {
+			log.Printf("interest: capitalising %s: %v", account.AccountNumber, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("interest run failed for %d of %d accounts", failed, len(accounts))
+	}
+	return nil
+}
+
+// accrue records the interest of every day from the last accrual (or the opening of the account) up to today.
+// Days that end with a negative balance accrue overdraft interest instead of the account's own rate.
+func (e *Engine) accrue(account *Account, today time.Time) error {
+	current, err := e.rate(account, today)
+	if err != nil {
+		return err
+	}
+	if current == "" && !e.chargesOverdraft(account) {
+		return nil
+	}
+
+	day := Day(account.Created)
+	last, err := e.store.GetLastInterestAccrual(account.AccountNumber)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		day = last.Day.AddDate(0, 0, 1)
+	}
+
+	for ; day.Before(today); day = day.AddDate(0, 0, 1) {
+		balance, err := e.store.GetBalanceAt(account.AccountNumber, day.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+		if balance == nil {
+			return errors.Wrapf(ErrAccountNotFound, "%s", account.AccountNumber)
+		}
+		rate := e.overdraftRate
+		if !balance.IsNegative() {
+			if rate, err = e.rate(account, day); err != nil {
+				return err
+			}
+		}
+		if rate == "" {
+			rate = "0"
+		}
+		accrual, err := AccrueInterest(account.AccountNumber, day, *balance, rate, e.dayCount)
+		if err != nil {
+			return err
+		}
+		if _, err := e.store.SaveInterestAccrual(accrual); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rate returns the annual rate the account earns on the given day, or "" if it earns no interest.
+func (e *Engine) rate(account *Account, day time.Time) (string, error) {
+	rate, err := e.store.GetInterestRate(account.AccountNumber, day)
+	if err != nil {
+		return "", err
+	}
+	if rate != nil {
+		return rate.AnnualRate, nil
+	}
+	if account.Type == SavingsAccount {
+		return e.savingsRate, nil
+	}
+	return "", nil
+}
+
+// chargesOverdraft reports whether the account may owe overdraft interest: it has an overdraft or is
+// still overdrawn after its limit was removed.
+func (e *Engine) chargesOverdraft(account *Account) bool {
+	return e.overdraftRate != "" && (account.OverdraftLimit.IsPositive() || account.Balance.IsNegative())
+}
@@ -0,0 +1,88 @@
+package interest
+
+import (
+	. "go-bank-v2/internal/infrastructure/postgres"
+	. "go-bank-v2/internal/types"
+	"testing"
+	"time"
+)
+
+// accountStore serves the accounts, balances and accruals the engine reads and records what it
+// saves and capitalises. Capitalising fails for accounts that can't be posted to, as in the ledger.
+type accountStore struct {
+	Store
+	accounts    []*Account
+	accruals    map[string][]*InterestAccrual
+	capitalised []string
+}
+
+func (s *accountStore) GetAllAccounts(...AccountStatus) ([]*Account, error) {
+	return s.accounts, nil
+}
+
+func (s *accountStore) account(accountNumber string) *Account {
+	for _, a := range s.accounts {
+		if a.AccountNumber == accountNumber {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *accountStore) GetBalanceAt(accountNumber string, _ time.Time) (*Money, error) {
+	return &s.account(accountNumber).Balance, nil
+}
+
+func (s *accountStore) GetInterestRate(string, time.Time) (*InterestRate, error) {
+	return nil, nil
+}
+
+func (s *accountStore) SaveInterestAccrual(accrual *InterestAccrual) (bool, error) {
+	s.accruals[accrual.AccountNumber] = append(s.accruals[accrual.AccountNumber], accrual)
+	return true, nil
+}
+
+func (s *accountStore) GetLastInterestAccrual(accountNumber string) (*InterestAccrual, error) {
+	accruals := s.accruals[accountNumber]
+	if len(accruals) == 0 {
+		return nil, nil
+	}
+	return accruals[len(accruals)-1], nil
+}
+
+func (s *accountStore) CapitaliseInterest(accountNumber string, _ time.Time) (*Transaction, error) {
+	if err := s.account(accountNumber).CheckCanPost(); err != nil {
+		return nil, err
+	}
+	s.capitalised = append(s.capitalised, accountNumber)
+	return &Transaction{}, nil
+}
+
+func TestRunLeavesAccrualsOfFrozenAccountsPending(t *testing.T) {
+	opened := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	store := &accountStore{
+		accounts: []*Account{
+			{AccountNumber: "100", Balance: NewMoney(100000, "EUR"), Type: SavingsAccount, Status: ActiveAccount, Created: opened},
+			{AccountNumber: "200", Balance: NewMoney(100000, "EUR"), Type: SavingsAccount, Status: FrozenAccount, Created: opened},
+			{AccountNumber: "300", Balance: NewMoney(100000, "EUR"), Type: SavingsAccount, Status: DormantAccount, Created: opened},
+		},
+		accruals: map[string][]*InterestAccrual{},
+	}
+	engine, err := NewEngine(Config{DayCount: "ACT/365", SavingsRate: "0.02", OverdraftRate: "0"}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, time.February, 3, 12, 0, 0, 0, time.UTC)
+	if err := engine.Run(now); err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range store.accounts {
+		if n := len(store.accruals[account.AccountNumber]); n != 33 {
+			t.Errorf("account %s (%s) accrued %d days, want 33", account.AccountNumber, account.Status, n)
+		}
+	}
+	if len(store.capitalised) != 1 || store.capitalised[0] != "100" {
+		t.Errorf("capitalised %v, want only the active account", store.capitalised)
+	}
+}
@@ -0,0 +1,12 @@
+package reconciliation
+
+import "time"
+
+type Config struct {
+	// Interval between runs, counted from midnight UTC so the default runs at the end of each day;
+	// zero disables in-process runs.
+	Interval  time.Duration `env:"RECONCILIATION_INTERVAL" envDefault:"24h"`
+	BatchSize int           `env:"RECONCILIATION_BATCH_SIZE" envDefault:"500"`
+	// ReportDir is where a JSON report of every run is written; empty writes none.
+	ReportDir string `env:"RECONCILIATION_REPORT_DIR"`
+}
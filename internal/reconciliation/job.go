@@ -0,0 +1,116 @@
+// Package reconciliation checks that the stored balance of every account matches the balance its
+// ledger entries add up to.
+package reconciliation
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/infrastructure/postgres"
+	. "go-bank-v2/internal/types"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+type Job struct {
+	store     Store
+	interval  time.Duration
+	batchSize int
+	reportDir string
+}
+
+func NewJob(config Config, store Store) (*Job, error) {
+	if config.BatchSize < 1 {
+		return nil, errors.New("reconciliation needs a positive batch size")
+	}
+	return &Job{
+		store:     store,
+		interval:  config.Interval,
+		batchSize: config.BatchSize,
+		reportDir: config.ReportDir,
+	}, nil
+}
+
+// Start runs the job in the background at every multiple of the interval since midnight UTC.
+func (j *Job) Start() {
+	if j.interval <= 0 {
+		return
+	}
+	go func() {
+		for {
+			now := time.Now()
+			time.Sleep(now.Truncate(j.interval).Add(j.interval).Sub(now))
+			if _, err := j.Run(time.Now()); err != nil {
+				log.Printf("reconciliation: %v", err)
+			}
+		}
+	}()
+}
+
+// Run reconciles every account, a batch of accounts per database transaction. The run and the
+// mismatches found are recorded as it goes, so a run that fails part way still reports what it
+// checked.
+func (j *Job) Run(now time.Time) (*ReconciliationRun, error) {
+	run := NewReconciliationRun(now)
+	if err := j.store.CreateReconciliationRun(run); err != nil {
+		return nil, err
+	}
+
+	err := j.reconcile(run)
+	run.Finish(time.Now(), err)
+	if err := j.store.FinishReconciliationRun(run); err != nil {
+		return run, err
+	}
+	if err := j.writeReport(run); err != nil {
+		return run, err
+	}
+	if err != nil {
+		return run, errors.Wrapf(err, "run %d", run.ID)
+	}
+	if len(run.Mismatches) > 0 {
+		log.Printf("reconciliation: run %d found %d of %d accounts out of balance", run.ID, len(run.Mismatches), run.Accounts)
+	}
+	return run, nil
+}
+
+func (j *Job) reconcile(run *ReconciliationRun) error {
+	accounts, err := j.store.GetAllAccounts()
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(accounts); start += j.batchSize {
+		end := start + j.batchSize
+		if end > len(accounts) {
+			end = len(accounts)
+		}
+		numbers := make([]string, 0, end-start)
+		for _, account := range accounts[start:end] {
+			numbers = append(numbers, account.AccountNumber)
+		}
+		mismatches, err := j.store.ReconcileBalances(run, numbers)
+		if err != nil {
+			return err
+		}
+		run.Accounts += len(numbers)
+		run.Mismatches = append(run.Mismatches, mismatches...)
+	}
+	return nil
+}
+
+// writeReport saves the run as reconciliation-<id>.json in the report directory.
+func (j *Job) writeReport(run *ReconciliationRun) error {
+	if j.reportDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(j.reportDir, 0o755); err != nil {
+		return err
+	}
+	report, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(j.reportDir, "reconciliation-"+strconv.FormatInt(run.ID, 10)+".json")
+	return os.WriteFile(name, append(report, '\n'), 0o644)
+}
@@ -0,0 +1,13 @@
+package scheduler
+
+import "time"
+
+type Config struct {
+	// Interval between checks for due standing orders and expired holds; zero disables the scheduler.
+	Interval time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"1m"`
+	// MaxAttempts is how often a rejected payment is tried before it is skipped.
+	MaxAttempts int `env:"SCHEDULER_MAX_ATTEMPTS" envDefault:"3"`
+	// RetryDelay is the wait between attempts of a rejected payment or loan instalment.
+	RetryDelay time.Duration `env:"SCHEDULER_RETRY_DELAY" envDefault:"4h"`
+	BatchSize  int           `env:"SCHEDULER_BATCH_SIZE" envDefault:"100"`
+}
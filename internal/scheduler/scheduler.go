@@ -0,0 +1,283 @@
+// Package scheduler runs time-driven work: it executes standing orders and collects loan
+// instalments when they fall due, pays out term deposits at maturity, releases authorization holds
+// and ends approval requests once they expire, purges expired idempotency keys, snapshots account
+// balances every night and charges maintenance fees every month.
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/infrastructure/postgres"
+	. "go-bank-v2/internal/types"
+	"log"
+	"strings"
+	"time"
+)
+
+// snapshotDelay is how long after midnight balances are snapshotted, so postings still in flight
+// at midnight are included.
+const snapshotDelay = 10 * time.Minute
+
+type Scheduler struct {
+	store     Store
+	policy    RetryPolicy
+	interval  time.Duration
+	batchSize int
+	// snapshotted is the last midnight balances were snapshotted at by this scheduler.
+	snapshotted time.Time
+	// feesCharged is the last month maintenance fees were charged for by this scheduler.
+	feesCharged time.Time
+}
+
+func NewScheduler(config Config, store Store) (*Scheduler, error) {
+	if config.MaxAttempts < 1 || config.BatchSize < 1 {
+		return nil, errors.New("scheduler needs at least one attempt per payment and a positive batch size")
+	}
+	return &Scheduler{
+		store:     store,
+		policy:    RetryPolicy{MaxAttempts: config.MaxAttempts, Delay: config.RetryDelay},
+		interval:  config.Interval,
+		batchSize: config.BatchSize,
+	}, nil
+}
+
+// Start checks for due payments, expired holds and balances to snapshot in the background every
+// configured interval.
+func (s *Scheduler) Start() {
+	if s.interval <= 0 {
+		return
+	}
+	go func() {
+		for {
+			if err := s.Run(time.Now()); err != nil {
+				log.Printf("scheduler: %v", err)
+			}
+			time.Sleep(s.interval)
+		}
+	}()
+}
+
+// Run snapshots balances if a day has passed, charges maintenance fees if a month has passed,
+// expires the holds, approval requests and idempotency keys, executes the standing orders, collects
+// the loan instalments due at now and processes the term deposits that have matured. Each job runs
+// even if another one failed; their errors are returned together.
+func (s *Scheduler) Run(now time.Time) error {
+	jobs := []func(time.Time) error{
+		s.snapshotBalances,
+		s.chargeMaintenanceFees,
+		s.expireHolds,
+		s.expireApprovalRequests,
+		s.purgeIdempotencyKeys,
+		s.executeStandingOrders,
+		s.collectLoanInstalments,
+		s.matureTermDeposits,
+	}
+	var failures []string
+	for _, job := range jobs {
+		if err := job(now); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// snapshotBalances records the balance at the last midnight (UTC) of every account that moved
+// since its previous snapshot, one batch at a time. Historical balance lookups start from these.
+func (s *Scheduler) snapshotBalances(now time.Time) error {
+	at := Day(now.Add(-snapshotDelay))
+	if !at.After(s.snapshotted) {
+		return nil
+	}
+	for {
+		recorded, err := s.store.SnapshotBalances(at, s.batchSize)
+		if err != nil {
+			return errors.Wrap(err, "snapshotting balances")
+		}
+		if recorded < s.batchSize {
+			break
+		}
+	}
+	s.snapshotted = at
+	return nil
+}
+
+// chargeMaintenanceFees charges the maintenance fee for the month before now to every account that
+// has not paid it yet, one account per transaction. An account that can't be charged is skipped and
+// tried again on the next run.
+func (s *Scheduler) chargeMaintenanceFees(now time.Time) error {
+	today := Day(now)
+	period := time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	if !period.After(s.feesCharged) {
+		return nil
+	}
+	after, failed := "", 0
+	for {
+		numbers, err := s.store.GetMaintenanceFeeAccounts(period, after, s.batchSize)
+		if err != nil {
+			return errors.Wrap(err, "charging maintenance fees")
+		}
+		for _, number := range numbers {
+			if _, err := s.store.ChargeMaintenanceFee(number, period); err != nil {
+				log.Printf("scheduler: maintenance fee of %s for %s: %v", number, period.Format("2006-01"), err)
+				failed++
+			}
+		}
+		if len(numbers) < s.batchSize {
+			break
+		}
+		after = numbers[len(numbers)-1]
+	}
+	if failed > 0 {
+		return errors.Errorf("%d accounts could not be charged the maintenance fee", failed)
+	}
+	s.feesCharged = period
+	return nil
+}
+
+// expireHolds frees the funds of every hold that expired before now, one batch per transaction.
+func (s *Scheduler) expireHolds(now time.Time) error {
+	for {
+		expired, err := s.store.ExpireHolds(now, s.batchSize)
+		if err != nil {
+			return errors.Wrap(err, "expiring holds")
+		}
+		if expired < s.batchSize {
+			return nil
+		}
+	}
+}
+
+// expireApprovalRequests ends every approval request no admin decided on before it expired, one
+// batch per transaction.
+func (s *Scheduler) expireApprovalRequests(now time.Time) error {
+	for {
+		expired, err := s.store.ExpireApprovalRequests(now, s.batchSize)
+		if err != nil {
+			return errors.Wrap(err, "expiring approval requests")
+		}
+		if expired < s.batchSize {
+			return nil
+		}
+	}
+}
+
+// purgeIdempotencyKeys deletes the idempotency keys whose retention window ended before now, one
+// batch at a time.
+func (s *Scheduler) purgeIdempotencyKeys(now time.Time) error {
+	for {
+		deleted, err := s.store.PurgeIdempotencyKeys(now, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if deleted < s.batchSize {
+			return nil
+		}
+	}
+}
+
+// executeStandingOrders executes every standing order due at now. Orders are claimed one by one,
+// so several instances may run side by side without paying an order twice.
+func (s *Scheduler) executeStandingOrders(now time.Time) error {
+	for {
+		ids, err := s.store.GetDueStandingOrders(now, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		executed, failed := 0, 0
+		for _, id := range ids {
+			execution, err := s.store.ExecuteStandingOrder(id, now, s.policy)
+			if err != nil {
+				log.Printf("scheduler: standing order %d: %v", id, err)
+				failed++
+				continue
+			}
+			if execution == nil {
+				continue
+			}
+			executed++
+			if execution.Status != ExecutionSucceeded {
+				log.Printf("scheduler: standing order %d due %s, attempt %d %s: %s",
+					id, execution.Due.Format("2006-01-02"), execution.Attempt, execution.Status, execution.Error)
+			}
+		}
+
+		if failed > 0 {
+			return errors.Errorf("%d standing orders could not be executed", failed)
+		}
+		// Stop when the last batch was not full or only held orders claimed by someone else.
+		if len(ids) < s.batchSize || executed == 0 {
+			return nil
+		}
+	}
+}
+
+// collectLoanInstalments collects every loan instalment due at now. Instalments in arrears are
+// tried again after the retry delay until they are paid.
+func (s *Scheduler) collectLoanInstalments(now time.Time) error {
+	for {
+		ids, err := s.store.GetDueLoanInstalments(now, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		collected, failed := 0, 0
+		for _, id := range ids {
+			instalment, err := s.store.CollectLoanInstalment(id, now, s.policy.Delay)
+			if err != nil {
+				log.Printf("scheduler: loan instalment %d: %v", id, err)
+				failed++
+				continue
+			}
+			if instalment == nil {
+				continue
+			}
+			collected++
+			if instalment.Status == OverdueInstalment {
+				log.Printf("scheduler: loan %d instalment %d due %s in arrears after %d attempts",
+					instalment.LoanID, instalment.Number, instalment.Due.Format("2006-01-02"), instalment.Attempts)
+			}
+		}
+
+		if failed > 0 {
+			return errors.Errorf("%d loan instalments could not be collected", failed)
+		}
+		if len(ids) < s.batchSize || collected == 0 {
+			return nil
+		}
+	}
+}
+
+// matureTermDeposits processes every deposit whose maturity date has been reached. Maturity dates
+// are days, so deposits mature with the first run of the day. A deposit that can't be paid out,
+// e.g. because its linked account was frozen, is tried again on the next run.
+func (s *Scheduler) matureTermDeposits(now time.Time) error {
+	for {
+		ids, err := s.store.GetMaturedTermDeposits(now, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		matured, failed := 0, 0
+		for _, id := range ids {
+			deposit, err := s.store.MatureTermDeposit(id, now)
+			if err != nil {
+				log.Printf("scheduler: term deposit %d: %v", id, err)
+				failed++
+				continue
+			}
+			if deposit != nil {
+				matured++
+			}
+		}
+
+		if failed > 0 {
+			return errors.Errorf("%d term deposits could not be paid out", failed)
+		}
+		if len(ids) < s.batchSize || matured == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package statement
+
+import (
+	"encoding/xml"
+	. "go-bank-v2/internal/types"
+	"io"
+	"strconv"
+	"time"
+)
+
+// camt.053 is the ISO 20022 bank-to-customer statement. Version 001.02 is the one accounting
+// software reads most widely; elements are written in the order its schema requires.
+const camtNamespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// camtMaxIDLength is the length of the Max35Text identifiers of the format.
+const camtMaxIDLength = 35
+
+type camtDocument struct {
+	XMLName   xml.Name      `xml:"Document"`
+	Namespace string        `xml:"xmlns,attr"`
+	Header    camtHeader    `xml:"BkToCstmrStmt>GrpHdr"`
+	Statement camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtHeader struct {
+	MessageID string `xml:"MsgId"`
+	Created   string `xml:"CreDtTm"`
+}
+
+type camtStatement struct {
+	ID       string        `xml:"Id"`
+	Created  string        `xml:"CreDtTm"`
+	From     string        `xml:"FrToDt>FrDtTm"`
+	To       string        `xml:"FrToDt>ToDtTm"`
+	Account  camtAccount   `xml:"Acct"`
+	Balances []camtBalance `xml:"Bal"`
+	Summary  camtSummary   `xml:"TxsSummry"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtAccount struct {
+	ID       string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+	Servicer string `xml:"Svcr>FinInstnId>Othr>Id"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtBalance struct {
+	Type   string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount camtAmount `xml:"Amt"`
+	Credit string     `xml:"CdtDbtInd"`
+	Date   string     `xml:"Dt>Dt"`
+}
+
+type camtSummary struct {
+	Total   camtTotal `xml:"TtlNtries"`
+	Credits camtCount `xml:"TtlCdtNtries"`
+	Debits  camtCount `xml:"TtlDbtNtries"`
+}
+
+type camtTotal struct {
+	Count  int    `xml:"NbOfNtries"`
+	Sum    string `xml:"Sum"`
+	Net    string `xml:"TtlNetNtryAmt"`
+	Credit string `xml:"CdtDbtInd"`
+}
+
+type camtCount struct {
+	Count int    `xml:"NbOfNtries"`
+	Sum   string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	Reference  string           `xml:"NtryRef"`
+	Amount     camtAmount       `xml:"Amt"`
+	Credit     string           `xml:"CdtDbtInd"`
+	Reversal   bool             `xml:"RvslInd,omitempty"`
+	Status     string           `xml:"Sts"`
+	Booked     string           `xml:"BookgDt>DtTm"`
+	Value      string           `xml:"ValDt>Dt"`
+	ServicerID string           `xml:"AcctSvcrRef"`
+	Code       string           `xml:"BkTxCd>Prtry>Cd"`
+	Details    *camtEntryDetail `xml:"NtryDtls>TxDtls,omitempty"`
+	Info       string           `xml:"AddtlNtryInf,omitempty"`
+}
+
+type camtEntryDetail struct {
+	EndToEndID string `xml:"Refs>EndToEndId,omitempty"`
+	Remittance string `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+func writeCamt053(w io.Writer, st *Statement) error {
+	id := camtStatementID(st)
+	doc := camtDocument{
+		Namespace: camtNamespace,
+		Header:    camtHeader{MessageID: id, Created: camtTime(st.Generated)},
+		Statement: camtStatement{
+			ID:      id,
+			Created: camtTime(st.Generated),
+			From:    camtTime(st.From),
+			To:      camtTime(periodEnd(st)),
+			Account: camtAccount{ID: st.AccountNumber, Currency: string(st.Currency), Servicer: st.BankID},
+			Balances: []camtBalance{
+				camtBalanceOf("OPBD", st.OpeningBalance, st.From),
+				camtBalanceOf("CLBD", st.ClosingBalance, periodEnd(st)),
+			},
+		},
+	}
+
+	credits, debits := Zero(st.Currency), Zero(st.Currency)
+	for _, m := range st.Movements {
+		var err error
+		if m.Amount.IsNegative() {
+			doc.Statement.Summary.Debits.Count++
+			debits, err = debits.Add(m.Amount.Abs())
+		} else {
+			doc.Statement.Summary.Credits.Count++
+			credits, err = credits.Add(m.Amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := camtEntry{
+			Reference:  strconv.FormatInt(m.EntryID, 10),
+			Amount:     camtAmount{Currency: string(m.Amount.Currency), Value: m.Amount.Abs().String()},
+			Credit:     creditDebit(m.Amount),
+			Reversal:   m.ReversalOf != 0,
+			Status:     "BOOK",
+			Booked:     camtTime(m.Booked),
+			Value:      m.Booked.UTC().Format("2006-01-02"),
+			ServicerID: strconv.FormatInt(m.TransactionID, 10),
+			Code:       string(m.Type),
+		}
+		if m.Reference != "" || m.Description != "" {
+			entry.Details = &camtEntryDetail{EndToEndID: truncate(m.Reference, 35), Remittance: truncate(m.Description, 140)}
+		}
+		// The indicator alone doesn't tell which entry is undone.
+		if m.ReversalOf != 0 {
+			entry.Info = "Reverses transaction " + strconv.FormatInt(m.ReversalOf, 10)
+		}
+		doc.Statement.Entries = append(doc.Statement.Entries, entry)
+	}
+
+	summary := &doc.Statement.Summary
+	summary.Credits.Sum, summary.Debits.Sum = credits.String(), debits.String()
+	total, err := credits.Add(debits)
+	if err != nil {
+		return err
+	}
+	net, err := credits.Sub(debits)
+	if err != nil {
+		return err
+	}
+	summary.Total = camtTotal{
+		Count:  len(st.Movements),
+		Sum:    total.String(),
+		Net:    net.Abs().String(),
+		Credit: creditDebit(net),
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// camtStatementID identifies the statement by the first day of its period and the account number
+// within the 35 characters the format allows. Long account numbers lose their leading digits, so the
+// serial and check digits that tell accounts of the bank apart are kept.
+func camtStatementID(st *Statement) string {
+	prefix := "STMT-" + st.From.UTC().Format("20060102") + "-"
+	account := st.AccountNumber
+	if n := camtMaxIDLength - len(prefix); len(account) > n {
+		account = account[len(account)-n:]
+	}
+	return prefix + account
+}
+
+func camtBalanceOf(code string, balance Money, at time.Time) camtBalance {
+	return camtBalance{
+		Type:   code,
+		Amount: camtAmount{Currency: string(balance.Currency), Value: balance.Abs().String()},
+		Credit: creditDebit(balance),
+		Date:   at.UTC().Format("2006-01-02"),
+	}
+}
+
+// creditDebit is the indicator the format carries in place of a sign; zero counts as credit.
+func creditDebit(m Money) string {
+	if m.IsNegative() {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+func camtTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// periodEnd is the last instant covered by the statement, whose period excludes its end.
+func periodEnd(st *Statement) time.Time {
+	return st.To.Add(-time.Nanosecond)
+}
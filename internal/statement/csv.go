@@ -0,0 +1,47 @@
+package statement
+
+import (
+	"encoding/csv"
+	. "go-bank-v2/internal/types"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{"Date", "Transaction", "Type", "Reference", "Description", "Reverses", "Amount", "Balance", "Currency"}
+
+// writeCSV writes one row per movement, framed by an opening and a closing balance row.
+func writeCSV(w io.Writer, st *Statement) error {
+	out := csv.NewWriter(w)
+	rows := [][]string{
+		csvHeader,
+		{st.From.Format(time.RFC3339), "", "", "", "Opening balance", "", "", st.OpeningBalance.String(), string(st.Currency)},
+	}
+	for _, m := range st.Movements {
+		rows = append(rows, []string{
+			m.Booked.Format(time.RFC3339),
+			strconv.FormatInt(m.TransactionID, 10),
+			string(m.Type),
+			m.Reference,
+			m.Description,
+			reverses(m),
+			m.Amount.String(),
+			m.Balance.String(),
+			string(m.Amount.Currency),
+		})
+	}
+	rows = append(rows, []string{st.To.Format(time.RFC3339), "", "", "", "Closing balance", "", "", st.ClosingBalance.String(), string(st.Currency)})
+
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Error()
+}
+
+// reverses names the transaction a reversal offsets, and is empty for every other movement.
+func reverses(m Movement) string {
+	if m.ReversalOf == 0 {
+		return ""
+	}
+	return strconv.FormatInt(m.ReversalOf, 10)
+}
@@ -0,0 +1,138 @@
+package statement
+
+import (
+	"bufio"
+	. "go-bank-v2/internal/types"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MT940 is the SWIFT customer statement message. Only the text block is written, the way banks
+// hand the file to customers; lines end in CRLF and hold only characters of the SWIFT X set.
+
+// mt940Narrative is the most the :86: field holds: 6 lines of 65 characters.
+const (
+	mt940NarrativeLines = 6
+	mt940LineLength     = 65
+)
+
+func writeMT940(w io.Writer, st *Statement) error {
+	out := bufio.NewWriter(w)
+	field := func(tag string, value string) {
+		out.WriteString(":" + tag + ":" + value + "\r\n")
+	}
+
+	field("20", swiftText("STMT"+st.From.UTC().Format("20060102"), 16))
+	field("25", swiftText(accountIdentification(st), 35))
+	field("28C", "1")
+	field("60F", mt940Balance(st.OpeningBalance, st.From))
+	for _, m := range st.Movements {
+		field("61", mt940StatementLine(m))
+		if narrative := mt940Narrative(m); narrative != "" {
+			field("86", narrative)
+		}
+	}
+	field("62F", mt940Balance(st.ClosingBalance, periodEnd(st)))
+	out.WriteString("-\r\n")
+	return out.Flush()
+}
+
+func accountIdentification(st *Statement) string {
+	if st.BankID == "" {
+		return st.AccountNumber
+	}
+	return st.BankID + "/" + st.AccountNumber
+}
+
+func mt940Balance(balance Money, at time.Time) string {
+	return mt940Mark(balance) + at.UTC().Format("060102") + string(balance.Currency) + mt940Amount(balance)
+}
+
+// mt940StatementLine is the :61: field: value date, entry date, debit/credit mark, amount,
+// transaction type, the customer's reference and the bank's reference.
+func mt940StatementLine(m Movement) string {
+	booked := m.Booked.UTC()
+	mark := mt940Mark(m.Amount)
+	if m.ReversalOf != 0 {
+		// Reversals are marked by the side of the entry they undo: a credit reverses a debit.
+		mark = "R" + mt940Mark(m.Amount.Neg())
+	}
+	// The reference may not contain the "//" that starts the bank's reference.
+	reference := strings.TrimSpace(strings.ReplaceAll(swiftText(m.Reference, 16), "/", " "))
+	if reference == "" {
+		reference = "NONREF"
+	}
+	return booked.Format("060102") + booked.Format("0102") + mark + mt940Amount(m.Amount) +
+		"N" + mt940TransactionType(m) + reference + "//" + strconv.FormatInt(m.TransactionID, 10)
+}
+
+func mt940TransactionType(m Movement) string {
+	switch m.Type {
+	case TransferTransaction, ClosureSweepTransaction:
+		return "TRF"
+	case InterestTransaction:
+		return "INT"
+	case FeeTransaction, MaintenanceFeeTransaction:
+		return "CHG"
+	}
+	return "MSC"
+}
+
+// mt940Narrative is the :86: field, the reference and description wrapped onto lines of the
+// allowed length.
+func mt940Narrative(m Movement) string {
+	text := strings.TrimSpace(swiftText(strings.TrimSpace(m.Reference+" "+m.Description), mt940NarrativeLines*mt940LineLength))
+	var lines []string
+	for text != "" && len(lines) < mt940NarrativeLines {
+		n := len(text)
+		if n > mt940LineLength {
+			n = mt940LineLength
+		}
+		line := text[:n]
+		// A line starting like this would be read as the next field or the end of the message.
+		if line[0] == ':' || line[0] == '-' {
+			line = " " + line[1:]
+		}
+		lines = append(lines, line)
+		text = text[n:]
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+// mt940Mark is the debit/credit mark; zero counts as credit.
+func mt940Mark(m Money) string {
+	if m.IsNegative() {
+		return "D"
+	}
+	return "C"
+}
+
+// mt940Amount writes the unsigned amount with a decimal comma, which the format requires even
+// for whole amounts.
+func mt940Amount(m Money) string {
+	s := strings.Replace(m.Abs().String(), ".", ",", 1)
+	if !strings.Contains(s, ",") {
+		s += ","
+	}
+	return s
+}
+
+// swiftText replaces every character outside the SWIFT X set with a space and cuts the result to
+// at most n characters.
+func swiftText(s string, n int) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("/-?:().,'+ ", r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+		if b.Len() == n {
+			break
+		}
+	}
+	return b.String()
+}
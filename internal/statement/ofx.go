@@ -0,0 +1,146 @@
+package statement
+
+import (
+	"encoding/xml"
+	. "go-bank-v2/internal/types"
+	"io"
+	"strconv"
+	"time"
+)
+
+// OFX 2.2 is the XML flavour of the Open Financial Exchange format read by personal finance software.
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+	`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+type ofxDocument struct {
+	XMLName xml.Name  `xml:"OFX"`
+	SignOn  ofxSignOn `xml:"SIGNONMSGSRSV1>SONRS"`
+	Bank    ofxBank   `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	Server   string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+}
+
+type ofxBank struct {
+	TransactionUID string       `xml:"TRNUID"`
+	Status         ofxStatus    `xml:"STATUS"`
+	Statement      ofxStatement `xml:"STMTRS"`
+}
+
+type ofxStatement struct {
+	Currency     string           `xml:"CURDEF"`
+	Account      ofxAccount       `xml:"BANKACCTFROM"`
+	Transactions ofxTransactions  `xml:"BANKTRANLIST"`
+	Ledger       ofxLedgerBalance `xml:"LEDGERBAL"`
+}
+
+type ofxAccount struct {
+	BankID    string `xml:"BANKID"`
+	AccountID string `xml:"ACCTID"`
+	Type      string `xml:"ACCTTYPE"`
+}
+
+type ofxTransactions struct {
+	Start        string           `xml:"DTSTART"`
+	End          string           `xml:"DTEND"`
+	Transactions []ofxTransaction `xml:"STMTTRN"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	FitID  string `xml:"FITID"`
+	Name   string `xml:"NAME,omitempty"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+type ofxLedgerBalance struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
+
+func writeOFX(w io.Writer, st *Statement) error {
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+	doc := ofxDocument{
+		SignOn: ofxSignOn{Status: ok, Server: ofxTime(st.Generated), Language: "ENG"},
+		Bank: ofxBank{
+			TransactionUID: "0",
+			Status:         ok,
+			Statement: ofxStatement{
+				Currency: string(st.Currency),
+				Account:  ofxAccount{BankID: st.BankID, AccountID: st.AccountNumber, Type: "CHECKING"},
+				Transactions: ofxTransactions{
+					Start: ofxTime(st.From),
+					End:   ofxTime(st.To),
+				},
+				Ledger: ofxLedgerBalance{Amount: st.ClosingBalance.String(), AsOf: ofxTime(st.To)},
+			},
+		},
+	}
+	for _, m := range st.Movements {
+		doc.Bank.Statement.Transactions.Transactions = append(doc.Bank.Statement.Transactions.Transactions, ofxTransaction{
+			Type:   ofxTransactionType(m),
+			Posted: ofxTime(m.Booked),
+			Amount: m.Amount.String(),
+			FitID:  strconv.FormatInt(m.EntryID, 10),
+			Name:   truncate(m.Reference, 32),
+			Memo:   truncate(m.Description, 255),
+		})
+	}
+
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func ofxTransactionType(m Movement) string {
+	switch m.Type {
+	case DepositTransaction:
+		return "DEP"
+	case WithdrawalTransaction:
+		return "CASH"
+	case TransferTransaction, ClosureSweepTransaction:
+		return "XFER"
+	case InterestTransaction:
+		return "INT"
+	case HoldCaptureTransaction:
+		return "POS"
+	case FeeTransaction:
+		return "FEE"
+	case MaintenanceFeeTransaction:
+		return "SRVCHG"
+	}
+	if m.Amount.IsNegative() {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// truncate cuts s to at most n runes, the field lengths the format allows.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
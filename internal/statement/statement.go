@@ -0,0 +1,70 @@
+// Package statement renders account statements in the export formats offered to customers.
+package statement
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	. "go-bank-v2/internal/types"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	JSON    Format = "json"
+	CSV     Format = "csv"
+	OFX     Format = "ofx"
+	Camt053 Format = "camt.053"
+	MT940   Format = "mt940"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported statement format")
+
+type encoder struct {
+	contentType string
+	extension   string
+	write       func(io.Writer, *Statement) error
+}
+
+var encoders = map[Format]encoder{
+	JSON:    {contentType: "application/json", extension: "json", write: writeJSON},
+	CSV:     {contentType: "text/csv", extension: "csv", write: writeCSV},
+	OFX:     {contentType: "application/x-ofx", extension: "ofx", write: writeOFX},
+	Camt053: {contentType: "application/xml", extension: "xml", write: writeCamt053},
+	MT940:   {contentType: "text/plain", extension: "sta", write: writeMT940},
+}
+
+// ParseFormat returns the format with the given name, defaulting to JSON when the name is empty.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return JSON, nil
+	}
+	f := Format(strings.ToLower(s))
+	if _, ok := encoders[f]; !ok {
+		return "", errors.Wrapf(ErrUnsupportedFormat, "%q", s)
+	}
+	return f, nil
+}
+
+func (f Format) ContentType() string {
+	return encoders[f].contentType
+}
+
+// FileName suggests a download name made of the account number and the first day of the period.
+func (f Format) FileName(st *Statement) string {
+	return "statement-" + st.AccountNumber + "-" + st.From.Format("2006-01-02") + "." + encoders[f].extension
+}
+
+// Write renders the statement in the given format.
+func Write(w io.Writer, f Format, st *Statement) error {
+	e, ok := encoders[f]
+	if !ok {
+		return errors.Wrapf(ErrUnsupportedFormat, "%q", f)
+	}
+	return e.write(w, st)
+}
+
+func writeJSON(w io.Writer, st *Statement) error {
+	return json.NewEncoder(w).Encode(st)
+}
@@ -0,0 +1,469 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	. "go-bank-v2/internal/types"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var (
+	periodFrom = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	periodTo   = time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	generated  = time.Date(2024, time.April, 1, 6, 30, 0, 0, time.UTC)
+)
+
+func booked(day int, hour int) time.Time {
+	return time.Date(2024, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+func testStatement(t *testing.T, currency Currency, opening int64, movements ...Movement) *Statement {
+	t.Helper()
+	number, err := FormatAccountNumber("1000", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := &Account{AccountNumber: number, Balance: NewMoney(0, currency)}
+	for i := range movements {
+		movements[i].EntryID = int64(100 + i)
+	}
+	st, err := NewStatement(account, "COBADEFFXXX", periodFrom, periodTo, NewMoney(opening, currency), movements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Generated = generated
+	return st
+}
+
+var goldenStatements = []struct {
+	name      string
+	statement func(t *testing.T) *Statement
+}{
+	{
+		name: "movements",
+		statement: func(t *testing.T) *Statement {
+			return testStatement(t, "EUR", 100000,
+				Movement{TransactionID: 11, Type: DepositTransaction, Reference: "CASH-0311", Description: "Cash deposit", Amount: NewMoney(25000, "EUR"), Booked: booked(3, 9)},
+				Movement{TransactionID: 12, Type: TransferTransaction, Reference: "INV-2024-17", Description: "Rent March", Amount: NewMoney(-12050, "EUR"), Booked: booked(5, 14)},
+				Movement{TransactionID: 13, Type: ReversalTransaction, Reference: "INV-2024-17", Description: "Reversal of rent March", ReversalOf: 12, Amount: NewMoney(12050, "EUR"), Booked: booked(6, 8)},
+				Movement{TransactionID: 14, Type: MaintenanceFeeTransaction, Description: "Monthly maintenance fee", Amount: NewMoney(-148, "EUR"), Booked: booked(31, 23)},
+			)
+		},
+	},
+	{
+		name: "empty",
+		statement: func(t *testing.T) *Statement {
+			return testStatement(t, "EUR", 4200)
+		},
+	},
+	{
+		name: "jpy",
+		statement: func(t *testing.T) *Statement {
+			return testStatement(t, "JPY", 10000,
+				Movement{TransactionID: 21, Type: WithdrawalTransaction, Description: "ATM withdrawal", Amount: NewMoney(-3500, "JPY"), Booked: booked(2, 11)},
+				Movement{TransactionID: 22, Type: InterestTransaction, Description: "Interest", Amount: NewMoney(1, "JPY"), Booked: booked(31, 23)},
+			)
+		},
+	},
+	{
+		name: "narrative",
+		statement: func(t *testing.T) *Statement {
+			return testStatement(t, "EUR", 0,
+				Movement{
+					TransactionID: 31,
+					Type:          TransferTransaction,
+					Reference:     "A//B_ref#1",
+					Description: "Zahlung für Müller & Söhne GmbH, Rechnung Nr. 2024_004-42 über 1.250,00 € " +
+						"inkl. Versand@Lager:Nord-Süd -- bitte bis 15.04. prüfen und freigeben, danke! " +
+						"Dieser Teil des Textes passt nicht mehr in die sechs erlaubten Zeilen und wird abgeschnitten",
+					Amount: NewMoney(125000, "EUR"),
+					Booked: booked(15, 10),
+				},
+			)
+		},
+	},
+}
+
+func TestGoldenStatements(t *testing.T) {
+	writers := []struct {
+		extension string
+		write     func(io.Writer, *Statement) error
+	}{
+		{extension: "camt053.xml", write: writeCamt053},
+		{extension: "mt940.sta", write: writeMT940},
+	}
+	for _, tt := range goldenStatements {
+		for _, w := range writers {
+			name := tt.name + "." + w.extension
+			t.Run(name, func(t *testing.T) {
+				var got bytes.Buffer
+				if err := w.write(&got, tt.statement(t)); err != nil {
+					t.Fatal(err)
+				}
+				golden := filepath.Join("testdata", name+".golden")
+				if *update {
+					if err := os.WriteFile(golden, got.Bytes(), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got.Bytes(), want) {
+					t.Errorf("output differs from %s:\n%s", golden, got.String())
+				}
+			})
+		}
+	}
+}
+
+// xmlNode is any element of a document, kept with its children in the order they were written.
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []xmlNode  `xml:",any"`
+}
+
+// path returns the elements at the end of the path of child names below n.
+func (n xmlNode) path(names ...string) []xmlNode {
+	nodes := []xmlNode{n}
+	for _, name := range names {
+		var next []xmlNode
+		for _, node := range nodes {
+			for _, child := range node.Children {
+				if child.XMLName.Local == name {
+					next = append(next, child)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// text returns the text of the only element at the path, failing the test if there is not exactly one.
+func (n xmlNode) text(t *testing.T, names ...string) string {
+	t.Helper()
+	nodes := n.path(names...)
+	if len(nodes) != 1 {
+		t.Fatalf("%s: found %d elements, want 1", strings.Join(names, "/"), len(nodes))
+	}
+	return strings.TrimSpace(nodes[0].Text)
+}
+
+// amount reads an amount element with its currency attribute, signed by the indicator next to it.
+func (n xmlNode) amount(t *testing.T, amount string, indicator string) Money {
+	t.Helper()
+	nodes := n.path(amount)
+	if len(nodes) != 1 || len(nodes[0].Attrs) != 1 || nodes[0].Attrs[0].Name.Local != "Ccy" {
+		t.Fatalf("%s: want one element with a Ccy attribute", amount)
+	}
+	return signedAmount(t, n.text(t, amount), Currency(nodes[0].Attrs[0].Value), n.text(t, indicator) == "DBIT")
+}
+
+func signedAmount(t *testing.T, value string, currency Currency, negative bool) Money {
+	t.Helper()
+	m, err := ParseMoney(value, currency)
+	if err != nil || m.IsNegative() {
+		t.Fatalf("invalid amount %q: %v", value, err)
+	}
+	if negative {
+		return m.Neg()
+	}
+	return m
+}
+
+// checkOrder fails unless the children of n appear in schema order and include the required ones.
+func checkOrder(t *testing.T, n xmlNode, schema []string, required ...string) {
+	t.Helper()
+	position := map[string]int{}
+	for i, name := range schema {
+		position[name] = i
+	}
+	last := -1
+	seen := map[string]bool{}
+	for _, child := range n.Children {
+		name := child.XMLName.Local
+		i, ok := position[name]
+		if !ok {
+			t.Errorf("%s: unexpected element %s", n.XMLName.Local, name)
+			continue
+		}
+		if i < last {
+			t.Errorf("%s: %s out of order", n.XMLName.Local, name)
+		}
+		last = i
+		seen[name] = true
+	}
+	for _, name := range required {
+		if !seen[name] {
+			t.Errorf("%s: required element %s missing", n.XMLName.Local, name)
+		}
+	}
+}
+
+var (
+	camtStatementSchema = []string{"Id", "ElctrncSeqNb", "LglSeqNb", "CreDtTm", "FrToDt", "CpyDplctInd", "RptgSrc",
+		"Acct", "RltdAcct", "Intrst", "Bal", "TxsSummry", "Ntry", "AddtlStmtInf"}
+	camtBalanceSchema = []string{"Tp", "CdtLine", "Amt", "CdtDbtInd", "Dt", "Avlbty"}
+	camtEntrySchema   = []string{"NtryRef", "Amt", "CdtDbtInd", "RvslInd", "Sts", "BookgDt", "ValDt", "AcctSvcrRef",
+		"Avlbty", "BkTxCd", "ComssnWvrInd", "AddtlInfInd", "AmtDtls", "Chrgs", "TechInptChanl", "Intrst", "NtryDtls",
+		"AddtlNtryInf"}
+)
+
+// checkCamt053 validates a statement against the structure rules of camt.053.001.02: namespace,
+// element order, identifier lengths and totals that reconcile with the entries and balances.
+func checkCamt053(t *testing.T, data []byte, st *Statement) {
+	t.Helper()
+	var doc xmlNode
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.XMLName.Local != "Document" || doc.XMLName.Space != camtNamespace {
+		t.Fatalf("root element %s in namespace %q", doc.XMLName.Local, doc.XMLName.Space)
+	}
+	checkOrder(t, doc.path("BkToCstmrStmt")[0], []string{"GrpHdr", "Stmt"}, "GrpHdr", "Stmt")
+	checkOrder(t, doc.path("BkToCstmrStmt", "GrpHdr")[0], []string{"MsgId", "CreDtTm"}, "MsgId", "CreDtTm")
+	for _, id := range []string{doc.text(t, "BkToCstmrStmt", "GrpHdr", "MsgId"), doc.text(t, "BkToCstmrStmt", "Stmt", "Id")} {
+		if id == "" || len(id) > 35 {
+			t.Errorf("identifier %q is not 1 to 35 characters", id)
+		}
+		if !strings.Contains(id, st.From.Format("20060102")) {
+			t.Errorf("identifier %q lacks the first day of the period", id)
+		}
+	}
+
+	stmt := doc.path("BkToCstmrStmt", "Stmt")[0]
+	checkOrder(t, stmt, camtStatementSchema, "Id", "CreDtTm", "Acct", "Bal")
+	if id := stmt.text(t, "Acct", "Id", "Othr", "Id"); id != st.AccountNumber {
+		t.Errorf("account %q, want %q", id, st.AccountNumber)
+	}
+
+	balances := stmt.path("Bal")
+	if len(balances) != 2 {
+		t.Fatalf("%d balances, want opening and closing", len(balances))
+	}
+	for i, code := range []string{"OPBD", "CLBD"} {
+		checkOrder(t, balances[i], camtBalanceSchema, "Tp", "Amt", "CdtDbtInd", "Dt")
+		if got := balances[i].text(t, "Tp", "CdOrPrtry", "Cd"); got != code {
+			t.Errorf("balance %d is %s, want %s", i, got, code)
+		}
+	}
+	opening := balances[0].amount(t, "Amt", "CdtDbtInd")
+	closing := balances[1].amount(t, "Amt", "CdtDbtInd")
+
+	credits, debits := Zero(st.Currency), Zero(st.Currency)
+	creditCount, debitCount := 0, 0
+	for _, entry := range stmt.path("Ntry") {
+		checkOrder(t, entry, camtEntrySchema, "Amt", "CdtDbtInd", "Sts", "BkTxCd")
+		amount := entry.amount(t, "Amt", "CdtDbtInd")
+		var err error
+		if amount.IsNegative() {
+			debitCount++
+			debits, err = debits.Add(amount.Abs())
+		} else {
+			creditCount++
+			credits, err = credits.Add(amount)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sts := entry.text(t, "Sts"); sts != "BOOK" {
+			t.Errorf("entry status %q, want BOOK", sts)
+		}
+		if len(entry.path("RvslInd")) > 0 && !strings.HasPrefix(entry.text(t, "AddtlNtryInf"), "Reverses transaction ") {
+			t.Errorf("reversal entry %s does not refer to the transaction it reverses", entry.text(t, "NtryRef"))
+		}
+	}
+
+	summary := stmt.path("TxsSummry")[0]
+	total, _ := credits.Add(debits)
+	net, _ := credits.Sub(debits)
+	checks := []struct {
+		name      string
+		got, want string
+	}{
+		{"TtlNtries/NbOfNtries", summary.text(t, "TtlNtries", "NbOfNtries"), strconv.Itoa(creditCount + debitCount)},
+		{"TtlNtries/Sum", summary.text(t, "TtlNtries", "Sum"), total.String()},
+		{"TtlCdtNtries/NbOfNtries", summary.text(t, "TtlCdtNtries", "NbOfNtries"), strconv.Itoa(creditCount)},
+		{"TtlCdtNtries/Sum", summary.text(t, "TtlCdtNtries", "Sum"), credits.String()},
+		{"TtlDbtNtries/NbOfNtries", summary.text(t, "TtlDbtNtries", "NbOfNtries"), strconv.Itoa(debitCount)},
+		{"TtlDbtNtries/Sum", summary.text(t, "TtlDbtNtries", "Sum"), debits.String()},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s is %s, want %s", c.name, c.got, c.want)
+		}
+	}
+	totals := summary.path("TtlNtries")[0]
+	if got := signedAmount(t, totals.text(t, "TtlNetNtryAmt"), st.Currency, totals.text(t, "CdtDbtInd") == "DBIT"); got != net {
+		t.Errorf("net entry amount %s, want %s", got.String(), net.String())
+	}
+	if want, _ := opening.Add(net); closing != want {
+		t.Errorf("closing balance %s does not follow from opening %s and entries %s", closing.String(), opening.String(), net.String())
+	}
+}
+
+var (
+	mt940Tags         = regexp.MustCompile(`^20 25 28C 60F( 61( 86)?)* 62F$`)
+	mt940BalanceField = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})(\d{1,12},\d{0,3})$`)
+	mt940Statement    = regexp.MustCompile(`^(\d{6})(\d{4})?(C|D|RC|RD)(\d{1,12},\d{0,3})N([A-Z0-9]{3})(.{1,16}?)(//.{1,16})?$`)
+	swiftX            = regexp.MustCompile(`^[a-zA-Z0-9/\-?:().,'+ ]*$`)
+)
+
+// mt940Value turns an MT940 amount into a signed amount of the currency.
+func mt940Value(t *testing.T, value string, currency Currency, negative bool) Money {
+	t.Helper()
+	return signedAmount(t, strings.TrimSuffix(strings.Replace(value, ",", ".", 1), "."), currency, negative)
+}
+
+// checkMT940 validates a statement against the structure rules of MT940: field sequence and
+// lengths, the SWIFT X character set, the :61: layout and balances that reconcile with the lines.
+func checkMT940(t *testing.T, data []byte, st *Statement) {
+	t.Helper()
+	text := string(data)
+	if !strings.HasSuffix(text, "\r\n-\r\n") {
+		t.Fatal("message does not end with -")
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\r\n-\r\n"), "\r\n")
+
+	type field struct {
+		tag   string
+		lines []string
+	}
+	var fields []field
+	for _, line := range lines {
+		if strings.Contains(line, "\n") || strings.Contains(line, "\r") {
+			t.Fatalf("line %q is not terminated by CRLF", line)
+		}
+		if !swiftX.MatchString(line) {
+			t.Errorf("line %q has characters outside the SWIFT X set", line)
+		}
+		if strings.HasPrefix(line, ":") {
+			tag, value, ok := strings.Cut(line[1:], ":")
+			if !ok {
+				t.Fatalf("malformed field %q", line)
+			}
+			fields = append(fields, field{tag: tag, lines: []string{value}})
+			continue
+		}
+		if len(fields) == 0 || fields[len(fields)-1].tag != "86" || strings.HasPrefix(line, "-") {
+			t.Fatalf("unexpected line %q", line)
+		}
+		fields[len(fields)-1].lines = append(fields[len(fields)-1].lines, line)
+	}
+
+	var tags []string
+	for _, f := range fields {
+		tags = append(tags, f.tag)
+	}
+	if !mt940Tags.MatchString(strings.Join(tags, " ")) {
+		t.Fatalf("field sequence %v", tags)
+	}
+
+	var opening, closing Money
+	net := Zero(st.Currency)
+	for _, f := range fields {
+		value := f.lines[0]
+		if f.tag != "86" && len(f.lines) > 1 {
+			t.Errorf(":%s: spans %d lines", f.tag, len(f.lines))
+		}
+		switch f.tag {
+		case "20":
+			if value == "" || len(value) > 16 {
+				t.Errorf(":20: %q is not 1 to 16 characters", value)
+			}
+		case "25":
+			if value == "" || len(value) > 35 {
+				t.Errorf(":25: %q is not 1 to 35 characters", value)
+			}
+		case "60F", "62F":
+			m := mt940BalanceField.FindStringSubmatch(value)
+			if m == nil || m[3] != string(st.Currency) {
+				t.Fatalf(":%s: %q does not match its pattern", f.tag, value)
+			}
+			balance := mt940Value(t, m[4], st.Currency, m[1] == "D")
+			if f.tag == "60F" {
+				opening = balance
+			} else {
+				closing = balance
+			}
+		case "61":
+			m := mt940Statement.FindStringSubmatch(value)
+			if m == nil || strings.Contains(m[6], "//") {
+				t.Fatalf(":61: %q does not match its pattern", value)
+			}
+			// A reversal of a debit credits the account and the other way round.
+			amount := mt940Value(t, m[4], st.Currency, m[3] == "D" || m[3] == "RC")
+			var err error
+			if net, err = net.Add(amount); err != nil {
+				t.Fatal(err)
+			}
+		case "86":
+			if len(f.lines) > 6 {
+				t.Errorf(":86: has %d lines, want at most 6", len(f.lines))
+			}
+			for _, line := range f.lines {
+				if len(line) > 65 {
+					t.Errorf(":86: line %q is longer than 65 characters", line)
+				}
+			}
+		}
+	}
+	if want, _ := opening.Add(net); closing != want {
+		t.Errorf("closing balance %s does not follow from opening %s and lines %s", closing.String(), opening.String(), net.String())
+	}
+}
+
+func TestCamtStatementID(t *testing.T) {
+	// Account numbers of a bank with a short and one with a long prefix; the latter don't fit whole.
+	ids := map[string]string{}
+	for _, prefix := range []string{"1000", "370400440012"} {
+		for _, serial := range []int64{42, 43} {
+			number, err := FormatAccountNumber(prefix, serial)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, from := range []time.Time{periodFrom, periodFrom.AddDate(0, 0, 1), periodFrom.AddDate(0, 1, 0)} {
+				statement := number + " from " + from.Format("2006-01-02")
+				id := camtStatementID(&Statement{AccountNumber: number, From: from})
+				if len(id) > 35 {
+					t.Errorf("statement of %s has identifier %q longer than 35 characters", statement, id)
+				}
+				if other, ok := ids[id]; ok {
+					t.Errorf("statements of %s and %s share identifier %q", other, statement, id)
+				}
+				ids[id] = statement
+			}
+		}
+	}
+}
+
+func TestStatementStructure(t *testing.T) {
+	for _, tt := range goldenStatements {
+		t.Run(tt.name, func(t *testing.T) {
+			st := tt.statement(t)
+			var camt, mt940 bytes.Buffer
+			if err := writeCamt053(&camt, st); err != nil {
+				t.Fatal(err)
+			}
+			checkCamt053(t, camt.Bytes(), st)
+			if err := writeMT940(&mt940, st); err != nil {
+				t.Fatal(err)
+			}
+			checkMT940(t, mt940.Bytes(), st)
+		})
+	}
+}
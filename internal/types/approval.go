@@ -0,0 +1,113 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"time"
+)
+
+type ApprovalStatus string
+
+const (
+	PendingRequest  ApprovalStatus = "pending"
+	ApprovedRequest ApprovalStatus = "approved"
+	RejectedRequest ApprovalStatus = "rejected"
+	// ExpiredRequest requests were not decided on within their lifetime and are never executed.
+	ExpiredRequest ApprovalStatus = "expired"
+)
+
+var (
+	ErrApprovalNotFound      = errors.New("approval request not found")
+	ErrApprovalNotPending    = errors.New("approval request is no longer pending")
+	ErrSelfApproval          = errors.New("approval request must be decided by another admin")
+	ErrInvalidApprovalStatus = errors.New("invalid approval status")
+)
+
+// ApprovalRequest is an admin operation held back until a second admin approves it. The request
+// is kept as the maker sent it, so approving it executes exactly what was asked for, on the maker's
+// behalf. ResponseStatus and Response are what the operation returned once executed.
+type ApprovalRequest struct {
+	ID             int64           `json:"id"`
+	Method         string          `json:"method" example:"DELETE"`
+	Route          string          `json:"route" example:"/users/:id"`
+	Path           string          `json:"path" example:"/users/42"`
+	Query          string          `json:"query,omitempty"`
+	Body           string          `json:"body,omitempty"`
+	ContentType    string          `json:"contentType,omitempty" example:"application/json"`
+	Amount         *Money          `json:"amount,omitempty"`
+	Status         ApprovalStatus  `json:"status"`
+	MakerID        string          `json:"makerId"`
+	CheckerID      string          `json:"checkerId,omitempty"`
+	Reason         string          `json:"reason,omitempty"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	Response       json.RawMessage `json:"response,omitempty" swaggertype:"object"`
+	Expires        time.Time       `json:"expires"`
+	Created        time.Time       `json:"created"`
+	Decided        *time.Time      `json:"decided,omitempty"`
+}
+
+func ParseApprovalStatus(s string) (ApprovalStatus, error) {
+	switch status := ApprovalStatus(s); status {
+	case PendingRequest, ApprovedRequest, RejectedRequest, ExpiredRequest:
+		return status, nil
+	}
+	return "", errors.Wrapf(ErrInvalidApprovalStatus, "%q", s)
+}
+
+// NewApprovalRequest holds back a request of the maker for ttl. Amount is what the operation
+// moves, if anything.
+func NewApprovalRequest(method string, route string, path string, query string, body []byte, contentType string, amount *Money, makerID string, ttl time.Duration) *ApprovalRequest {
+	now := time.Now()
+	return &ApprovalRequest{
+		Method:      method,
+		Route:       route,
+		Path:        path,
+		Query:       query,
+		Body:        string(body),
+		ContentType: contentType,
+		Amount:      amount,
+		Status:      PendingRequest,
+		MakerID:     makerID,
+		Expires:     now.Add(ttl),
+		Created:     now,
+	}
+}
+
+// Operation names the request for logs and the audit trail, e.g. "DELETE /users/42".
+func (r *ApprovalRequest) Operation() string {
+	if r.Query != "" {
+		return r.Method + " " + r.Path + "?" + r.Query
+	}
+	return r.Method + " " + r.Path
+}
+
+// CheckDecidable fails unless the request is still pending at now and the checker is not its maker.
+func (r *ApprovalRequest) CheckDecidable(checkerID string, now time.Time) error {
+	if r.Status != PendingRequest {
+		return errors.Wrapf(ErrApprovalNotPending, "approval request %d is %s", r.ID, r.Status)
+	}
+	if !now.Before(r.Expires) {
+		return errors.Wrapf(ErrApprovalNotPending, "approval request %d has expired", r.ID)
+	}
+	if r.MakerID == checkerID {
+		return errors.Wrapf(ErrSelfApproval, "approval request %d", r.ID)
+	}
+	return nil
+}
+
+// Decide records the outcome of a pending request. Checker is empty for expired requests.
+func (r *ApprovalRequest) Decide(status ApprovalStatus, checkerID string, reason string, now time.Time) {
+	r.Status = status
+	r.CheckerID = checkerID
+	r.Reason = reason
+	r.Decided = &now
+}
+
+// Complete records the response of an approved request. Bodies that are not JSON are dropped.
+func (r *ApprovalRequest) Complete(status int, body []byte) {
+	r.ResponseStatus = status
+	r.Response = nil
+	if len(body) > 0 && json.Valid(body) {
+		r.Response = body
+	}
+}
@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+// SystemActor is the actor of the changes the bank's background jobs make on their own.
+const SystemActor = "system"
+
+// AuditEvent records who performed a privileged operation, on what and why.
+type AuditEvent struct {
+	ID       int64     `json:"id"`
+	ActorID  string    `json:"actorId"`
+	Action   string    `json:"action"`
+	Entity   string    `json:"entity"`
+	EntityID string    `json:"entityId"`
+	Details  string    `json:"details"`
+	Created  time.Time `json:"created"`
+}
+
+func NewAuditEvent(actorID string, action string, entity string, entityID string, details string) *AuditEvent {
+	return &AuditEvent{
+		ActorID:  actorID,
+		Action:   action,
+		Entity:   entity,
+		EntityID: entityID,
+		Details:  details,
+		Created:  time.Now(),
+	}
+}
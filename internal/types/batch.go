@@ -0,0 +1,181 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+// BatchFormat is the file format a payment batch was uploaded in.
+type BatchFormat string
+
+const (
+	CSVBatch     BatchFormat = "csv"
+	Pain001Batch BatchFormat = "pain.001"
+)
+
+type BatchStatus string
+
+const (
+	// PendingBatch batches have been validated and wait for confirmation.
+	PendingBatch BatchStatus = "pending"
+	// ExecutedBatch batches were confirmed; each line records whether its payment went through.
+	ExecutedBatch BatchStatus = "executed"
+)
+
+type PaymentLineStatus string
+
+const (
+	ValidLine     PaymentLineStatus = "valid"
+	InvalidLine   PaymentLineStatus = "invalid"
+	SucceededLine PaymentLineStatus = "succeeded"
+	FailedLine    PaymentLineStatus = "failed"
+)
+
+var (
+	ErrInvalidBatchFile  = errors.New("invalid payment file")
+	ErrBatchNotFound     = errors.New("payment batch not found")
+	ErrBatchNotPending   = errors.New("payment batch has already been executed")
+	ErrEmptyPaymentBatch = errors.New("payment batch has no valid lines")
+)
+
+// PaymentInstruction is one payment as read from an uploaded file, before validation.
+// DebtorAccount and Currency are only known for formats that carry them.
+type PaymentInstruction struct {
+	Line          int
+	DebtorAccount string
+	ToAccount     string
+	Name          string
+	Amount        string
+	Currency      Currency
+	Reference     string
+}
+
+// PaymentBatch is a set of transfers from one account, uploaded as a file, previewed and then
+// executed on confirmation. Total is the sum of the valid lines.
+type PaymentBatch struct {
+	ID            int64          `json:"id"`
+	AccountNumber string         `json:"accountNumber"`
+	Format        BatchFormat    `json:"format"`
+	Status        BatchStatus    `json:"status"`
+	Total         Money          `json:"total"`
+	ValidLines    int            `json:"validLines"`
+	InvalidLines  int            `json:"invalidLines"`
+	CreatedBy     string         `json:"createdBy"`
+	Created       time.Time      `json:"created"`
+	Executed      *time.Time     `json:"executed,omitempty"`
+	Lines         []*PaymentLine `json:"lines,omitempty"`
+}
+
+type PaymentLine struct {
+	ID         int64             `json:"id"`
+	BatchID    int64             `json:"batchId"`
+	Line       int               `json:"line"`
+	ToAccount  string            `json:"toAccount"`
+	Name       string            `json:"name,omitempty"`
+	Amount     Money             `json:"amount"`
+	Reference  string            `json:"reference"`
+	Status     PaymentLineStatus `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	TransferID int64             `json:"transferId,omitempty"`
+}
+
+// NewPaymentBatch checks every instruction on its own and turns it into a batch line. Lines that
+// can't be paid are kept with status invalid and the reason, so the preview shows them.
+func NewPaymentBatch(account *Account, format BatchFormat, instructions []PaymentInstruction, createdBy string) *PaymentBatch {
+	batch := &PaymentBatch{
+		AccountNumber: account.AccountNumber,
+		Format:        format,
+		Status:        PendingBatch,
+		Total:         Zero(account.Balance.Currency),
+		CreatedBy:     createdBy,
+		Created:       time.Now(),
+	}
+	for _, in := range instructions {
+		line := &PaymentLine{
+			Line:      in.Line,
+			ToAccount: in.ToAccount,
+			Name:      in.Name,
+			Amount:    Zero(account.Balance.Currency),
+			Reference: in.Reference,
+			Status:    ValidLine,
+		}
+		if err := line.parse(account, in); err != nil {
+			line.Invalidate(err)
+		}
+		batch.Lines = append(batch.Lines, line)
+	}
+	return batch
+}
+
+func (l *PaymentLine) parse(account *Account, in PaymentInstruction) error {
+	if in.DebtorAccount != "" && in.DebtorAccount != account.AccountNumber {
+		return errors.Wrapf(ErrInvalidBatchFile, "debtor account %s is not %s", in.DebtorAccount, account.AccountNumber)
+	}
+	if in.Currency != "" && in.Currency != account.Balance.Currency {
+		return errors.Wrapf(ErrCurrencyMismatch, "account %s is held in %s", account.AccountNumber, account.Balance.Currency)
+	}
+	if err := ValidateAccountNumber(in.ToAccount); err != nil {
+		return err
+	}
+	if in.ToAccount == account.AccountNumber {
+		return ErrSameAccount
+	}
+	amount, err := ParseMoney(in.Amount, account.Balance.Currency)
+	if err != nil {
+		return err
+	}
+	if !amount.IsPositive() {
+		return errors.Wrap(ErrInvalidAmount, "amount must be positive")
+	}
+	l.Amount = amount
+	return nil
+}
+
+// Invalidate marks the line as one that won't be paid.
+func (l *PaymentLine) Invalidate(err error) {
+	l.Status = InvalidLine
+	l.Error = err.Error()
+}
+
+// Summarise recomputes the totals of the batch from its lines.
+func (b *PaymentBatch) Summarise() error {
+	b.Total = Zero(b.Total.Currency)
+	b.ValidLines, b.InvalidLines = 0, 0
+	for _, line := range b.Lines {
+		if line.Status == InvalidLine {
+			b.InvalidLines++
+			continue
+		}
+		b.ValidLines++
+		total, err := b.Total.Add(line.Amount)
+		if err != nil {
+			return err
+		}
+		b.Total = total
+	}
+	return nil
+}
+
+// CheckFunds invalidates the lines the account can't cover, taking the lines in file order so the
+// preview shows which payments would go through as things stand.
+func (b *PaymentBatch) CheckFunds(account *Account) error {
+	available, err := account.AvailableBalance()
+	if err != nil {
+		return err
+	}
+	for _, line := range b.Lines {
+		if line.Status != ValidLine {
+			continue
+		}
+		after, err := available.Sub(line.Amount)
+		if err != nil {
+			return err
+		}
+		if err := account.CheckBalance(after); err != nil {
+			line.Invalidate(err)
+			continue
+		}
+		available = after
+	}
+	return nil
+}
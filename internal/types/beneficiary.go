@@ -0,0 +1,62 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+const maxNicknameLength = 70
+
+var (
+	ErrBeneficiaryNotFound   = errors.New("beneficiary not found")
+	ErrDuplicateBeneficiary  = errors.New("beneficiary already saved")
+	ErrInvalidBeneficiary    = errors.New("invalid beneficiary")
+	ErrExternalBeneficiary   = errors.New("payments to other banks are not supported")
+	ErrBeneficiaryCoolingOff = errors.New("beneficiary is still in its cooling-off period")
+)
+
+// Beneficiary is a payee a user saved for later transfers. External beneficiaries hold accounts
+// at other banks; their account numbers are only checked for a valid format.
+type Beneficiary struct {
+	ID            int64     `json:"id"`
+	UserID        string    `json:"userId"`
+	Nickname      string    `json:"nickname"`
+	AccountNumber string    `json:"accountNumber"`
+	Reference     string    `json:"reference,omitempty"`
+	External      bool      `json:"external"`
+	Created       time.Time `json:"created"`
+}
+
+func NewBeneficiary(userID string, nickname string, accountNumber string, reference string) (*Beneficiary, error) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname == "" || len(nickname) > maxNicknameLength {
+		return nil, errors.Wrapf(ErrInvalidBeneficiary, "nickname must be 1 to %d characters", maxNicknameLength)
+	}
+	if err := ValidateAccountNumber(accountNumber); err != nil {
+		return nil, err
+	}
+	return &Beneficiary{
+		UserID:        userID,
+		Nickname:      nickname,
+		AccountNumber: accountNumber,
+		Reference:     strings.TrimSpace(reference),
+		Created:       time.Now(),
+	}, nil
+}
+
+// CheckCoolingOff rejects a transfer of amount to a beneficiary saved less than period ago if the
+// amount is larger than threshold. Both amounts must be in the same currency; a nil amount could not
+// be converted and counts as large.
+func (b *Beneficiary) CheckCoolingOff(now time.Time, period time.Duration, amount *Money, threshold Money) error {
+	ends := b.Created.Add(period)
+	if !now.Before(ends) {
+		return nil
+	}
+	if amount != nil {
+		if cmp, err := amount.Cmp(threshold); err != nil || cmp <= 0 {
+			return err
+		}
+	}
+	return errors.Wrapf(ErrBeneficiaryCoolingOff, "transfers above %s are possible from %s", threshold.Format(), ends.Format(time.RFC3339))
+}
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// FeeOperation is what a fee is charged for.
+type FeeOperation string
+
+const (
+	// MaintenanceFee is charged once a month for every account, tiered by its balance.
+	MaintenanceFee FeeOperation = "maintenance"
+	// TransferFee is charged on every transfer, tiered by the amount sent.
+	TransferFee FeeOperation = "transfer"
+	// FxFee is charged on top of the transfer fee when a transfer is converted into another currency.
+	FxFee FeeOperation = "fx"
+)
+
+type FeeStatus string
+
+const (
+	ChargedFee FeeStatus = "charged"
+	// WaivedFee fees were refunded to the account by an admin.
+	WaivedFee FeeStatus = "waived"
+)
+
+var (
+	ErrInvalidFeeRule   = errors.New("invalid fee rule")
+	ErrFeeRuleNotFound  = errors.New("fee rule not found")
+	ErrFeeNotFound      = errors.New("fee not found")
+	ErrFeeAlreadyWaived = errors.New("fee has already been waived")
+)
+
+// FeeRule is one tier of the fee schedule: operations of MinAmount or more on accounts of
+// AccountType held in the currency of MinAmount cost Fixed plus Rate of the amount. The tier with
+// the highest MinAmount not above the amount applies; amounts below every tier are free.
+type FeeRule struct {
+	ID          int64        `json:"id"`
+	AccountType AccountType  `json:"accountType"`
+	Operation   FeeOperation `json:"operation"`
+	MinAmount   Money        `json:"minAmount"`
+	Fixed       Money        `json:"fixed"`
+	Rate        string       `json:"rate" example:"0.001"`
+	CreatedBy   string       `json:"createdBy"`
+	Created     time.Time    `json:"created"`
+}
+
+func ParseFeeOperation(s string) (FeeOperation, error) {
+	switch o := FeeOperation(s); o {
+	case MaintenanceFee, TransferFee, FxFee:
+		return o, nil
+	}
+	return "", errors.Wrapf(ErrInvalidFeeRule, "unknown operation %q", s)
+}
+
+func NewFeeRule(accountType AccountType, operation FeeOperation, minAmount Money, fixed Money, rate string, createdBy string) (*FeeRule, error) {
+	if accountType != CurrentAccount && accountType != SavingsAccount {
+		return nil, errors.Wrap(ErrInvalidFeeRule, "fees are only charged on current and savings accounts")
+	}
+	if minAmount.IsNegative() || fixed.IsNegative() {
+		return nil, errors.Wrap(ErrInvalidFeeRule, "amounts must not be negative")
+	}
+	if minAmount.Currency != fixed.Currency {
+		return nil, errors.Wrapf(ErrCurrencyMismatch, "fixed fee must be in %s", minAmount.Currency)
+	}
+	if _, err := ParseInterestRate(rate); err != nil {
+		return nil, errors.Wrapf(ErrInvalidFeeRule, "rate %q must be a fraction between 0 and 1", rate)
+	}
+	return &FeeRule{
+		AccountType: accountType,
+		Operation:   operation,
+		MinAmount:   minAmount,
+		Fixed:       fixed,
+		Rate:        strings.TrimSpace(rate),
+		CreatedBy:   createdBy,
+		Created:     time.Now(),
+	}, nil
+}
+
+// Calculate returns the fee the rule charges on amount, rounded to the minor unit.
+func (r *FeeRule) Calculate(amount Money) (Money, error) {
+	rate, err := ParseInterestRate(r.Rate)
+	if err != nil {
+		return Money{}, err
+	}
+	variable, err := MoneyFromRat(new(big.Rat).Mul(amount.Abs().Rat(), rate), r.Fixed.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return r.Fixed.Add(variable)
+}
+
+// Fee is a charge booked on an account according to the fee schedule. Basis is the amount the fee
+// was calculated on: the transfer amount or, for maintenance fees, the balance at charging time.
+// Maintenance fees carry the month they are for in Period; months no tier covers are recorded
+// without a rule and a zero amount.
+type Fee struct {
+	ID                  int64        `json:"id"`
+	AccountNumber       string       `json:"accountNumber"`
+	Operation           FeeOperation `json:"operation"`
+	RuleID              int64        `json:"ruleId,omitempty"`
+	Basis               Money        `json:"basis"`
+	Amount              Money        `json:"amount"`
+	TransferID          int64        `json:"transferId,omitempty"`
+	Period              *time.Time   `json:"period,omitempty"`
+	Status              FeeStatus    `json:"status"`
+	TransactionID       int64        `json:"transactionId,omitempty"`
+	WaivedBy            string       `json:"waivedBy,omitempty"`
+	WaiverReason        string       `json:"waiverReason,omitempty"`
+	WaiverTransactionID int64        `json:"waiverTransactionId,omitempty"`
+	Waived              *time.Time   `json:"waived,omitempty"`
+	Created             time.Time    `json:"created"`
+}
+
+// NewFee prepares the fee of an operation; Apply fills in the amount once the rule is known.
+func NewFee(accountNumber string, operation FeeOperation, basis Money) *Fee {
+	return &Fee{
+		AccountNumber: accountNumber,
+		Operation:     operation,
+		Basis:         basis,
+		Amount:        Zero(basis.Currency),
+		Status:        ChargedFee,
+		Created:       time.Now(),
+	}
+}
+
+// Apply calculates the fee with the rule of the schedule that covers it.
+func (f *Fee) Apply(rule *FeeRule) error {
+	amount, err := rule.Calculate(f.Basis)
+	if err != nil {
+		return err
+	}
+	f.RuleID = rule.ID
+	f.Amount = amount
+	return nil
+}
+
+// Transaction books the fee as a posting of its own, so it shows up as a separate statement line.
+// Maintenance fees are booked even if they take the account over its limit.
+func (f *Fee) Transaction(feeIncomeAccount string, reference string) *Transaction {
+	txType, description := FeeTransaction, "Transfer fee"
+	switch f.Operation {
+	case FxFee:
+		description = "FX fee"
+	case MaintenanceFee:
+		txType, description = MaintenanceFeeTransaction, "Maintenance fee"
+		if f.Period != nil {
+			description += " " + f.Period.Format("January 2006")
+		}
+	}
+	t := NewTransaction(txType, reference, description)
+	return t.Debit(f.AccountNumber, f.Amount).Credit(feeIncomeAccount, f.Amount)
+}
+
+// CheckWaivable fails for fees that have already been refunded.
+func (f *Fee) CheckWaivable() error {
+	if f.Status == WaivedFee {
+		return errors.Wrapf(ErrFeeAlreadyWaived, "fee %d", f.ID)
+	}
+	return nil
+}
+
+// Waive records that an admin refunded the fee with the given transaction.
+func (f *Fee) Waive(actorID string, reason string, now time.Time, transactionID int64) {
+	f.Status = WaivedFee
+	f.WaivedBy = actorID
+	f.WaiverReason = reason
+	f.WaiverTransactionID = transactionID
+	f.Waived = &now
+}
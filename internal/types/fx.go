@@ -0,0 +1,122 @@
+package types
+
+import (
+	"encoding/csv"
+	"github.com/pkg/errors"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+	ErrNoExchangeRate      = errors.New("no exchange rate available")
+)
+
+// ExchangeRate states that one unit of Base buys Rate units of Quote from ValidFrom onwards.
+type ExchangeRate struct {
+	ID        int64     `json:"id"`
+	Base      Currency  `json:"base"`
+	Quote     Currency  `json:"quote"`
+	Rate      string    `json:"rate" example:"1.0850"`
+	ValidFrom time.Time `json:"validFrom"`
+	Created   time.Time `json:"created"`
+}
+
+func NewExchangeRate(base Currency, quote Currency, rate string, validFrom time.Time) (*ExchangeRate, error) {
+	if base == quote {
+		return nil, errors.Wrap(ErrInvalidExchangeRate, "base and quote currency must differ")
+	}
+	for _, c := range []Currency{base, quote} {
+		if _, err := ParseCurrency(string(c)); err != nil {
+			return nil, err
+		}
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || r.Sign() <= 0 {
+		return nil, errors.Wrapf(ErrInvalidExchangeRate, "%q", rate)
+	}
+	return &ExchangeRate{
+		Base:      base,
+		Quote:     quote,
+		Rate:      strings.TrimSpace(rate),
+		ValidFrom: validFrom,
+		Created:   time.Now(),
+	}, nil
+}
+
+func (r *ExchangeRate) rat() *big.Rat {
+	rate, _ := new(big.Rat).SetString(r.Rate)
+	return rate
+}
+
+// Inverse returns the rate for the opposite direction.
+func (r *ExchangeRate) Inverse() *ExchangeRate {
+	return &ExchangeRate{
+		ID:        r.ID,
+		Base:      r.Quote,
+		Quote:     r.Base,
+		Rate:      new(big.Rat).Inv(r.rat()).FloatString(10),
+		ValidFrom: r.ValidFrom,
+		Created:   r.Created,
+	}
+}
+
+// Convert exchanges an amount held in the base currency into the quote currency.
+func (r *ExchangeRate) Convert(m Money) (Money, error) {
+	if m.Currency != r.Base {
+		return Money{}, errors.Wrapf(ErrCurrencyMismatch, "rate converts %s, not %s", r.Base, m.Currency)
+	}
+	return MoneyFromRat(new(big.Rat).Mul(m.Rat(), r.rat()), r.Quote)
+}
+
+// ParseExchangeRatesCSV reads rates from CSV lines of the form base,quote,rate,validFrom.
+// validFrom is either a date (2006-01-02) or an RFC 3339 timestamp. A header line is optional.
+func ParseExchangeRatesCSV(r io.Reader) ([]*ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	var rates []*ExchangeRate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "base") {
+			continue
+		}
+
+		base, err := ParseCurrency(record[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+		quote, err := ParseCurrency(record[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+		validFrom, err := ParseDateOrTime(record[3])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+		rate, err := NewExchangeRate(base, quote, record[2], validFrom)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// ParseDateOrTime accepts either a plain date, taken as midnight UTC, or an RFC 3339 timestamp.
+func ParseDateOrTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+type HoldStatus string
+
+const (
+	ActiveHold   HoldStatus = "active"
+	CapturedHold HoldStatus = "captured"
+	ReleasedHold HoldStatus = "released"
+	ExpiredHold  HoldStatus = "expired"
+)
+
+var (
+	ErrHoldNotFound        = errors.New("hold not found")
+	ErrHoldNotActive       = errors.New("hold is no longer active")
+	ErrCaptureExceedsHold  = errors.New("capture exceeds the amount still held")
+	ErrInvalidHoldDuration = errors.New("hold must expire in the future")
+)
+
+// Hold reserves funds on an account, typically for a card payment that is authorized now and
+// settled later. Captured is the part already posted to the ledger; the rest stays reserved
+// until it is captured, released or the hold expires.
+type Hold struct {
+	ID            int64      `json:"id"`
+	AccountNumber string     `json:"accountNumber"`
+	Amount        Money      `json:"amount"`
+	Captured      Money      `json:"captured"`
+	Reference     string     `json:"reference"`
+	Status        HoldStatus `json:"status"`
+	InitiatedBy   string     `json:"initiatedBy"`
+	Expires       time.Time  `json:"expires"`
+	Created       time.Time  `json:"created"`
+	// LimitUsed is what placing the hold took off the limits of LimitUserID, in the currency limits
+	// are kept in. The part that is never captured is given back when the hold ends.
+	LimitUsed   Money  `json:"-"`
+	LimitUserID string `json:"-"`
+}
+
+// HoldCapture is the result of capturing a hold: the updated hold and the posting it produced.
+type HoldCapture struct {
+	Hold        *Hold        `json:"hold"`
+	Transaction *Transaction `json:"transaction"`
+}
+
+func NewHold(accountNumber string, amount Money, reference string, expires time.Time, initiatedBy string) (*Hold, error) {
+	if !amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "hold amount must be positive")
+	}
+	now := time.Now()
+	if !expires.After(now) {
+		return nil, ErrInvalidHoldDuration
+	}
+	return &Hold{
+		AccountNumber: accountNumber,
+		Amount:        amount,
+		Captured:      Zero(amount.Currency),
+		Reference:     reference,
+		Status:        ActiveHold,
+		InitiatedBy:   initiatedBy,
+		Expires:       expires,
+		Created:       now,
+	}, nil
+}
+
+// Remaining is the amount the hold still reserves.
+func (h *Hold) Remaining() Money {
+	if h.Status != ActiveHold {
+		return Zero(h.Amount.Currency)
+	}
+	remaining, _ := h.Amount.Sub(h.Captured)
+	return remaining
+}
+
+// Capture settles part or all of the remaining amount at the given time. The hold is done once
+// nothing remains. A hold can't be captured once it has expired, even if it wasn't ended yet.
+func (h *Hold) Capture(amount Money, at time.Time) error {
+	if h.Status != ActiveHold {
+		return errors.Wrapf(ErrHoldNotActive, "hold %d is %s", h.ID, h.Status)
+	}
+	if !at.Before(h.Expires) {
+		return errors.Wrapf(ErrHoldNotActive, "hold %d expired at %s", h.ID, h.Expires.Format(time.RFC3339))
+	}
+	if !amount.IsPositive() {
+		return errors.Wrap(ErrInvalidAmount, "capture amount must be positive")
+	}
+	if cmp, err := amount.Cmp(h.Remaining()); err != nil {
+		return err
+	} else if cmp > 0 {
+		return errors.Wrapf(ErrCaptureExceedsHold, "%s still held", h.Remaining().Format())
+	}
+	captured, err := h.Captured.Add(amount)
+	if err != nil {
+		return err
+	}
+	h.Captured = captured
+	if captured == h.Amount {
+		h.Status = CapturedHold
+	}
+	return nil
+}
+
+// UnusedLimit is the part of LimitUsed that the given amount, still reserved when the hold ends,
+// stands for.
+func (h *Hold) UnusedLimit(remaining Money) (Money, error) {
+	if !h.LimitUsed.IsPositive() || !remaining.IsPositive() {
+		return Zero(h.LimitUsed.Currency), nil
+	}
+	share := new(big.Rat).SetFrac64(remaining.Amount, h.Amount.Amount)
+	return MoneyFromRat(share.Mul(share, h.LimitUsed.Rat()), h.LimitUsed.Currency)
+}
+
+// Release ends the hold, freeing whatever it still reserves.
+func (h *Hold) Release() error {
+	return h.end(ReleasedHold)
+}
+
+// Expire ends a hold that was neither captured nor released in time.
+func (h *Hold) Expire() error {
+	return h.end(ExpiredHold)
+}
+
+func (h *Hold) end(status HoldStatus) error {
+	if h.Status != ActiveHold {
+		return errors.Wrapf(ErrHoldNotActive, "hold %d is %s", h.ID, h.Status)
+	}
+	h.Status = status
+	return nil
+}
+
+// NewHoldCapture posts a captured amount from the account to the clearing account.
+func NewHoldCapture(hold *Hold, clearingAccount string, amount Money) *Transaction {
+	t := NewTransaction(HoldCaptureTransaction, hold.Reference, "Capture of hold "+strconv.FormatInt(hold.ID, 10))
+	return t.Debit(hold.AccountNumber, amount).Credit(clearingAccount, amount)
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+// HolderRole is what a holder may do with a joint account.
+type HolderRole string
+
+const (
+	// OwnerRole holders may move money and manage the other holders.
+	OwnerRole HolderRole = "owner"
+	// SignatoryRole holders may move money.
+	SignatoryRole HolderRole = "signatory"
+	// ViewerRole holders may only look at the account.
+	ViewerRole HolderRole = "viewer"
+)
+
+var (
+	ErrInvalidHolderRole = errors.New("invalid holder role")
+	ErrHolderNotFound    = errors.New("account holder not found")
+	ErrLastOwner         = errors.New("an account needs at least one owner")
+	ErrHolderNotAllowed  = errors.New("user may not move money from this account")
+)
+
+// AccountHolder gives a user access to an account. Every customer account has at least one holder
+// with the owner role; Account.OwnerID is the primary owner.
+type AccountHolder struct {
+	AccountNumber string     `json:"accountNumber"`
+	UserID        string     `json:"userId"`
+	Role          HolderRole `json:"role"`
+	AddedBy       string     `json:"addedBy"`
+	Created       time.Time  `json:"created"`
+}
+
+func ParseHolderRole(s string) (HolderRole, error) {
+	switch r := HolderRole(s); r {
+	case OwnerRole, SignatoryRole, ViewerRole:
+		return r, nil
+	}
+	return "", errors.Wrapf(ErrInvalidHolderRole, "%q", s)
+}
+
+func NewAccountHolder(accountNumber string, userID string, role HolderRole, addedBy string) *AccountHolder {
+	return &AccountHolder{
+		AccountNumber: accountNumber,
+		UserID:        userID,
+		Role:          role,
+		AddedBy:       addedBy,
+		Created:       time.Now(),
+	}
+}
+
+// CanMoveMoney reports whether the holder may pay out of the account.
+func (h *AccountHolder) CanMoveMoney() bool {
+	return h.Role == OwnerRole || h.Role == SignatoryRole
+}
+
+// CanManageHolders reports whether the holder may add and remove other holders.
+func (h *AccountHolder) CanManageHolders() bool {
+	return h.Role == OwnerRole
+}
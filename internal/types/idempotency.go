@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// IdempotencyRecord remembers the outcome of a mutating request so a retry with the same
+// Idempotency-Key replays the stored response instead of repeating the side effect.
+type IdempotencyRecord struct {
+	Key         string
+	CallerID    string
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	Body        []byte
+	Created     time.Time
+	Expires     time.Time
+}
+
+func NewIdempotencyRecord(key string, callerID string, fingerprint string, ttl time.Duration) *IdempotencyRecord {
+	now := time.Now()
+	return &IdempotencyRecord{
+		Key:         key,
+		CallerID:    callerID,
+		Fingerprint: fingerprint,
+		Created:     now,
+		Expires:     now.Add(ttl),
+	}
+}
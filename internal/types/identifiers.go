@@ -0,0 +1,76 @@
+package types
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/pkg/errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Account numbers are the bank prefix followed by a zero-padded serial number and two
+// ISO 7064 MOD 97-10 check digits, the same scheme IBANs use.
+const accountSerialDigits = 10
+
+var (
+	ErrInvalidAccountNumber = errors.New("invalid account number")
+	ErrInvalidUserID        = errors.New("invalid user ID")
+)
+
+var (
+	digitsPattern = regexp.MustCompile(`^[0-9]+$`)
+	uuidPattern   = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// FormatAccountNumber builds the account number for the given serial under the bank prefix.
+func FormatAccountNumber(prefix string, serial int64) (string, error) {
+	if prefix == "" || !digitsPattern.MatchString(prefix) {
+		return "", errors.Errorf("account number prefix %q must consist of digits", prefix)
+	}
+	if serial <= 0 || len(strconv.FormatInt(serial, 10)) > accountSerialDigits {
+		return "", errors.Errorf("account serial %d out of range", serial)
+	}
+	base := fmt.Sprintf("%s%0*d", prefix, accountSerialDigits, serial)
+	return fmt.Sprintf("%s%02d", base, 98-mod97(base+"00")), nil
+}
+
+// ValidateAccountNumber checks the format and check digits of an account number.
+func ValidateAccountNumber(accountNumber string) error {
+	if len(accountNumber) < accountSerialDigits+3 || !digitsPattern.MatchString(accountNumber) || mod97(accountNumber) != 1 {
+		return errors.Wrapf(ErrInvalidAccountNumber, "%q", accountNumber)
+	}
+	return nil
+}
+
+// IsBankAccountNumber reports whether a valid account number was issued under the given bank prefix.
+func IsBankAccountNumber(accountNumber string, prefix string) bool {
+	return len(accountNumber) == len(prefix)+accountSerialDigits+2 && strings.HasPrefix(accountNumber, prefix)
+}
+
+// mod97 computes the remainder of a decimal digit string of any length divided by 97.
+func mod97(digits string) int {
+	remainder := 0
+	for _, d := range digits {
+		remainder = (remainder*10 + int(d-'0')) % 97
+	}
+	return remainder
+}
+
+// NewUserID returns a random (version 4) UUID so user identifiers cannot be guessed.
+func NewUserID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+func ValidateUserID(id string) error {
+	if !uuidPattern.MatchString(id) {
+		return errors.Wrapf(ErrInvalidUserID, "%q", id)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// DayCount is the convention used to turn a number of days into a fraction of a year.
+type DayCount string
+
+const (
+	Actual365    DayCount = "ACT/365"
+	Actual360    DayCount = "ACT/360"
+	ActualActual DayCount = "ACT/ACT"
+	Thirty360    DayCount = "30/360"
+)
+
+var (
+	ErrInvalidInterestRate = errors.New("invalid interest rate")
+	ErrInvalidDayCount     = errors.New("invalid day count convention")
+)
+
+// accrualScale is the number of decimal places (in major units) kept for daily accruals.
+const accrualScale = 10
+
+func ParseDayCount(s string) (DayCount, error) {
+	d := DayCount(strings.ToUpper(strings.TrimSpace(s)))
+	switch d {
+	case Actual365, Actual360, ActualActual, Thirty360:
+		return d, nil
+	}
+	return "", errors.Wrapf(ErrInvalidDayCount, "%q", s)
+}
+
+// YearFraction returns the part of a year between two dates under the convention.
+// Only the calendar dates of from and to are taken into account.
+func (d DayCount) YearFraction(from time.Time, to time.Time) *big.Rat {
+	switch d {
+	case Actual360:
+		return big.NewRat(days(from, to), 360)
+	case ActualActual:
+		// Split the period at year boundaries so each part is divided by the length of its own year.
+		fraction := new(big.Rat)
+		for from.Before(to) {
+			yearStart := time.Date(from.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+			yearEnd := yearStart.AddDate(1, 0, 0)
+			end := to
+			if yearEnd.Before(to) {
+				end = yearEnd
+			}
+			fraction.Add(fraction, big.NewRat(days(from, end), days(yearStart, yearEnd)))
+			from = end
+		}
+		return fraction
+	case Thirty360:
+		// 30E/360: every month counts as 30 days, the 31st as the 30th.
+		d1, d2 := from.Day(), to.Day()
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 {
+			d2 = 30
+		}
+		n := 360*(to.Year()-from.Year()) + 30*(int(to.Month())-int(from.Month())) + d2 - d1
+		return big.NewRat(int64(n), 360)
+	default:
+		return big.NewRat(days(from, to), 365)
+	}
+}
+
+// days counts the calendar days between the dates of two points in time.
+func days(from time.Time, to time.Time) int64 {
+	return int64(Day(to).Sub(Day(from)).Hours()) / 24
+}
+
+// Day truncates a point in time to midnight UTC of its date.
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseInterestRate parses an annual rate given as a fraction, e.g. "0.025" for 2.5% p.a.
+func ParseInterestRate(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() < 0 || r.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, errors.Wrapf(ErrInvalidInterestRate, "%q", s)
+	}
+	return r, nil
+}
+
+// InterestRate is the annual rate an account earns from ValidFrom onwards.
+type InterestRate struct {
+	ID            int64     `json:"id"`
+	AccountNumber string    `json:"accountNumber"`
+	AnnualRate    string    `json:"annualRate" example:"0.025"`
+	ValidFrom     time.Time `json:"validFrom"`
+	Created       time.Time `json:"created"`
+}
+
+func NewInterestRate(accountNumber string, annualRate string, validFrom time.Time) (*InterestRate, error) {
+	if _, err := ParseInterestRate(annualRate); err != nil {
+		return nil, err
+	}
+	return &InterestRate{
+		AccountNumber: accountNumber,
+		AnnualRate:    strings.TrimSpace(annualRate),
+		ValidFrom:     validFrom,
+		Created:       time.Now(),
+	}, nil
+}
+
+// InterestAccrual is the interest earned by an account on a single day. Amounts are kept
+// unrounded (in major units) and only rounded once, when the accruals are capitalised.
+type InterestAccrual struct {
+	AccountNumber string    `json:"accountNumber"`
+	Day           time.Time `json:"day"`
+	Balance       Money     `json:"balance"`
+	AnnualRate    string    `json:"annualRate"`
+	DayCount      DayCount  `json:"dayCount"`
+	Amount        string    `json:"amount"`
+	TransactionID int64     `json:"transactionId,omitempty"`
+	Created       time.Time `json:"created"`
+}
+
+// AccrueInterest computes the interest on the end-of-day balance of day. A negative balance
+// accrues a negative amount, i.e. interest owed by the customer; the caller picks the rate.
+func AccrueInterest(accountNumber string, day time.Time, balance Money, annualRate string, dayCount DayCount) (*InterestAccrual, error) {
+	rate, err := ParseInterestRate(annualRate)
+	if err != nil {
+		return nil, err
+	}
+	day = Day(day)
+	amount := new(big.Rat).Mul(balance.Rat(), rate)
+	amount.Mul(amount, dayCount.YearFraction(day, day.AddDate(0, 0, 1)))
+	return &InterestAccrual{
+		AccountNumber: accountNumber,
+		Day:           day,
+		Balance:       balance,
+		AnnualRate:    annualRate,
+		DayCount:      dayCount,
+		Amount:        amount.FloatString(accrualScale),
+		Created:       time.Now(),
+	}, nil
+}
+
+// SumAccruals adds up accrued interest and rounds the total to the minor unit of the currency.
+func SumAccruals(accruals []*InterestAccrual, currency Currency) (Money, error) {
+	total := new(big.Rat)
+	for _, a := range accruals {
+		amount, ok := new(big.Rat).SetString(a.Amount)
+		if !ok {
+			return Money{}, errors.Wrapf(ErrInvalidAmount, "accrual of %s on %s", a.AccountNumber, a.Day.Format("2006-01-02"))
+		}
+		total.Add(total, amount)
+	}
+	return MoneyFromRat(total, currency)
+}
+
+// NewInterestPayment credits the interest accrued on an account over [from, to), paid out of the
+// interest expense account.
+func NewInterestPayment(accountNumber string, expenseAccount string, amount Money, from time.Time, to time.Time) (*Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "interest amount must be positive")
+	}
+	reference := "Interest " + from.Format("2006-01-02") + " - " + to.AddDate(0, 0, -1).Format("2006-01-02")
+	t := NewTransaction(InterestTransaction, reference, "Interest capitalisation")
+	return t.Debit(expenseAccount, amount).Credit(accountNumber, amount), nil
+}
+
+// NewInterestCharge debits the overdraft interest accrued on an account over [from, to), paid into
+// the interest income account.
+func NewInterestCharge(accountNumber string, incomeAccount string, amount Money, from time.Time, to time.Time) (*Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "interest amount must be positive")
+	}
+	reference := "Overdraft interest " + from.Format("2006-01-02") + " - " + to.AddDate(0, 0, -1).Format("2006-01-02")
+	t := NewTransaction(InterestTransaction, reference, "Overdraft interest")
+	return t.Debit(accountNumber, amount).Credit(incomeAccount, amount), nil
+}
@@ -0,0 +1,179 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+type TransactionType string
+
+const (
+	OpeningBalanceTransaction        TransactionType = "opening_balance"
+	AdjustmentTransaction            TransactionType = "adjustment"
+	DepositTransaction               TransactionType = "deposit"
+	WithdrawalTransaction            TransactionType = "withdrawal"
+	TransferTransaction              TransactionType = "transfer"
+	ClosureSweepTransaction          TransactionType = "closure_sweep"
+	InterestTransaction              TransactionType = "interest"
+	HoldCaptureTransaction           TransactionType = "hold_capture"
+	ReversalTransaction              TransactionType = "reversal"
+	LoanDisbursementTransaction      TransactionType = "loan_disbursement"
+	LoanRepaymentTransaction         TransactionType = "loan_repayment"
+	TermDepositFundingTransaction    TransactionType = "term_deposit_funding"
+	TermDepositMaturityTransaction   TransactionType = "term_deposit_maturity"
+	TermDepositWithdrawalTransaction TransactionType = "term_deposit_withdrawal"
+	FeeTransaction                   TransactionType = "fee"
+	MaintenanceFeeTransaction        TransactionType = "maintenance_fee"
+)
+
+type EntryDirection string
+
+const (
+	Debit  EntryDirection = "debit"
+	Credit EntryDirection = "credit"
+)
+
+// SystemAccountCode identifies an internal bank account used as the other side of customer postings.
+type SystemAccountCode string
+
+const (
+	OpeningBalanceSystemAccount SystemAccountCode = "opening_balance"
+	AdjustmentSystemAccount     SystemAccountCode = "adjustment"
+	// ClearingSystemAccount is the counterpart of money entering or leaving the bank.
+	ClearingSystemAccount SystemAccountCode = "clearing"
+	// FxPositionSystemAccount absorbs the currency legs of cross-currency postings.
+	FxPositionSystemAccount SystemAccountCode = "fx_position"
+	// InterestExpenseSystemAccount pays the interest credited to customer accounts and term deposits.
+	InterestExpenseSystemAccount SystemAccountCode = "interest_expense"
+	// InterestIncomeSystemAccount receives the overdraft and loan interest charged to customer accounts.
+	InterestIncomeSystemAccount SystemAccountCode = "interest_income"
+	// FeeIncomeSystemAccount receives the fees charged to customers.
+	FeeIncomeSystemAccount SystemAccountCode = "fee_income"
+)
+
+var (
+	ErrUnbalancedTransaction = errors.New("transaction debits and credits do not balance")
+	ErrEmptyTransaction      = errors.New("transaction needs at least one debit and one credit")
+	ErrAccountNotFound       = errors.New("account not found")
+	ErrTransactionNotFound   = errors.New("transaction not found")
+)
+
+// Transaction groups the ledger entries of a single posting. Every transaction must balance:
+// per currency, the sum of its debits equals the sum of its credits. Cross-currency postings
+// record the applied ExchangeRate; their entries carry the amounts in both currencies.
+// Reversals name the transaction they compensate in ReversalOf. Payouts are charged to the limits
+// of the user in InitiatedBy, which is not stored with the transaction.
+type Transaction struct {
+	ID           int64           `json:"id"`
+	Type         TransactionType `json:"type"`
+	Reference    string          `json:"reference"`
+	Description  string          `json:"description"`
+	ExchangeRate string          `json:"exchangeRate,omitempty"`
+	ReversalOf   int64           `json:"reversalOf,omitempty"`
+	Created      time.Time       `json:"created"`
+	Entries      []LedgerEntry   `json:"entries"`
+	InitiatedBy  string          `json:"-"`
+}
+
+// LedgerEntry is one side of a transaction. Credits increase an account balance, debits decrease it.
+type LedgerEntry struct {
+	ID            int64          `json:"id"`
+	TransactionID int64          `json:"transactionId"`
+	AccountNumber string         `json:"accountNumber"`
+	Direction     EntryDirection `json:"direction"`
+	Amount        Money          `json:"amount"`
+	Created       time.Time      `json:"created"`
+}
+
+func NewTransaction(txType TransactionType, reference string, description string) *Transaction {
+	return &Transaction{
+		Type:        txType,
+		Reference:   reference,
+		Description: description,
+		Created:     time.Now(),
+	}
+}
+
+func (t *Transaction) Debit(accountNumber string, amount Money) *Transaction {
+	return t.addEntry(accountNumber, Debit, amount)
+}
+
+func (t *Transaction) Credit(accountNumber string, amount Money) *Transaction {
+	return t.addEntry(accountNumber, Credit, amount)
+}
+
+func (t *Transaction) addEntry(accountNumber string, direction EntryDirection, amount Money) *Transaction {
+	t.Entries = append(t.Entries, LedgerEntry{
+		AccountNumber: accountNumber,
+		Direction:     direction,
+		Amount:        amount,
+		Created:       t.Created,
+	})
+	return t
+}
+
+// EnforcesLimits reports whether the accounts debited by the transaction have to stay within their
+// available funds. Interest and maintenance fees the bank charges itself are booked even if they
+// take an account over its limit, and a loan disbursement puts the loan account into debt by design.
+func (t *Transaction) EnforcesLimits() bool {
+	return t.Type != InterestTransaction && t.Type != MaintenanceFeeTransaction && t.Type != LoanDisbursementTransaction
+}
+
+// NewDeposit credits the account with money received through the clearing account.
+func NewDeposit(accountNumber string, clearingAccount string, amount Money, reference string, reason string) (*Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "deposit amount must be positive")
+	}
+	t := NewTransaction(DepositTransaction, reference, reason)
+	return t.Debit(clearingAccount, amount).Credit(accountNumber, amount), nil
+}
+
+// NewWithdrawal debits the account with money paid out through the clearing account.
+func NewWithdrawal(accountNumber string, clearingAccount string, amount Money, reference string, reason string) (*Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "withdrawal amount must be positive")
+	}
+	t := NewTransaction(WithdrawalTransaction, reference, reason)
+	return t.Debit(accountNumber, amount).Credit(clearingAccount, amount), nil
+}
+
+// Validate checks that every entry carries a positive amount and that the transaction balances.
+func (t *Transaction) Validate() error {
+	var debits, credits int
+	balance := map[Currency]int64{}
+	for _, e := range t.Entries {
+		if !e.Amount.IsPositive() {
+			return errors.Wrapf(ErrInvalidAmount, "entry amount %s must be positive", e.Amount.Format())
+		}
+		switch e.Direction {
+		case Debit:
+			debits++
+		case Credit:
+			credits++
+		default:
+			return errors.Errorf("invalid entry direction %q", e.Direction)
+		}
+		sum, err := NewMoney(balance[e.Amount.Currency], e.Amount.Currency).Add(e.SignedAmount())
+		if err != nil {
+			return err
+		}
+		balance[e.Amount.Currency] = sum.Amount
+	}
+	if debits == 0 || credits == 0 {
+		return ErrEmptyTransaction
+	}
+	for currency, amount := range balance {
+		if amount != 0 {
+			return errors.Wrapf(ErrUnbalancedTransaction, "off by %s", NewMoney(amount, currency).Format())
+		}
+	}
+	return nil
+}
+
+// SignedAmount returns the effect of the entry on the account balance.
+func (e LedgerEntry) SignedAmount() Money {
+	if e.Direction == Debit {
+		return e.Amount.Neg()
+	}
+	return e.Amount
+}
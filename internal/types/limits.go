@@ -0,0 +1,150 @@
+package types
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"time"
+)
+
+type LimitPeriod string
+
+const (
+	DailyLimit   LimitPeriod = "daily"
+	MonthlyLimit LimitPeriod = "monthly"
+)
+
+var (
+	ErrLimitExceeded = errors.New("limit_exceeded")
+	ErrInvalidLimit  = errors.New("invalid limit")
+)
+
+// LimitExceededError rejects a payment that would take a user over one of their limits.
+// It reports the headroom left in the period and matches ErrLimitExceeded.
+type LimitExceededError struct {
+	Period    LimitPeriod
+	Limit     Money
+	Remaining Money
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %s, %s remaining", ErrLimitExceeded, e.Period, e.Limit.Format(), e.Remaining.Format())
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// UserLimits caps what a user may pay out per calendar day and month (UTC). A zero limit means no
+// limit. Users without limits of their own get the configured defaults.
+type UserLimits struct {
+	UserID  string    `json:"userId"`
+	Daily   Money     `json:"daily"`
+	Monthly Money     `json:"monthly"`
+	Custom  bool      `json:"custom"`
+	Updated time.Time `json:"updated"`
+}
+
+func NewUserLimits(userID string, daily Money, monthly Money) (*UserLimits, error) {
+	if daily.Currency != monthly.Currency {
+		return nil, errors.Wrap(ErrCurrencyMismatch, "daily and monthly limit")
+	}
+	if daily.IsNegative() || monthly.IsNegative() {
+		return nil, errors.Wrap(ErrInvalidLimit, "limits must not be negative")
+	}
+	if daily.IsPositive() && monthly.IsPositive() {
+		if cmp, _ := daily.Cmp(monthly); cmp > 0 {
+			return nil, errors.Wrap(ErrInvalidLimit, "daily limit exceeds monthly limit")
+		}
+	}
+	return &UserLimits{
+		UserID:  userID,
+		Daily:   daily,
+		Monthly: monthly,
+		Custom:  true,
+		Updated: time.Now(),
+	}, nil
+}
+
+// Limit returns the limit of the given period.
+func (l *UserLimits) Limit(period LimitPeriod) Money {
+	if period == DailyLimit {
+		return l.Daily
+	}
+	return l.Monthly
+}
+
+// PeriodStart returns the start of the limit period containing t.
+func PeriodStart(period LimitPeriod, t time.Time) time.Time {
+	day := Day(t)
+	if period == DailyLimit {
+		return day
+	}
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodEnd returns the end of the limit period starting at start.
+func PeriodEnd(period LimitPeriod, start time.Time) time.Time {
+	if period == DailyLimit {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// LimitUsage is how much of a limit has been used in the current period.
+type LimitUsage struct {
+	Period    LimitPeriod `json:"period"`
+	Start     time.Time   `json:"start"`
+	End       time.Time   `json:"end"`
+	Limit     Money       `json:"limit"`
+	Used      Money       `json:"used"`
+	Remaining *Money      `json:"remaining,omitempty"`
+}
+
+// NewLimitUsage reports the usage of a period. Remaining is left out when the period has no limit.
+func NewLimitUsage(period LimitPeriod, at time.Time, limit Money, used Money) (LimitUsage, error) {
+	start := PeriodStart(period, at)
+	usage := LimitUsage{Period: period, Start: start, End: PeriodEnd(period, start), Limit: limit, Used: used}
+	if limit.IsPositive() {
+		remaining, err := limit.Sub(used)
+		if err != nil {
+			return LimitUsage{}, err
+		}
+		if remaining.IsNegative() {
+			remaining = Zero(limit.Currency)
+		}
+		usage.Remaining = &remaining
+	}
+	return usage, nil
+}
+
+// Check rejects amount if it does not fit into what is left of the limit.
+func (u LimitUsage) Check(amount Money) error {
+	if u.Remaining == nil {
+		return nil
+	}
+	if cmp, err := amount.Cmp(*u.Remaining); err != nil {
+		return err
+	} else if cmp > 0 {
+		return &LimitExceededError{Period: u.Period, Limit: u.Limit, Remaining: *u.Remaining}
+	}
+	return nil
+}
+
+// Limits is the current state of a user's limits.
+type Limits struct {
+	UserID  string     `json:"userId"`
+	Custom  bool       `json:"custom"`
+	Daily   LimitUsage `json:"daily"`
+	Monthly LimitUsage `json:"monthly"`
+}
+
+// Unlimited reports whether the user may pay out any amount.
+func (l *Limits) Unlimited() bool {
+	return l.Daily.Remaining == nil && l.Monthly.Remaining == nil
+}
+
+// CountsTowardsLimits reports whether the money a transaction takes out of customer accounts is
+// limited: payments and withdrawals are, bank-initiated postings are not.
+func (t *Transaction) CountsTowardsLimits() bool {
+	return t.Type == TransferTransaction || t.Type == WithdrawalTransaction
+}
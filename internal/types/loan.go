@@ -0,0 +1,329 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// LoanAccount accounts carry the debt of a loan as a negative balance. They only move money
+// through their loan: the disbursement and the principal part of every instalment.
+const LoanAccount AccountType = "loan"
+
+type RepaymentFrequency string
+
+const (
+	WeeklyRepayment    RepaymentFrequency = "weekly"
+	MonthlyRepayment   RepaymentFrequency = "monthly"
+	QuarterlyRepayment RepaymentFrequency = "quarterly"
+)
+
+// AmortizationMethod decides how the principal is spread over the instalments. Annuity loans pay
+// the same amount every period; linear loans repay the same principal every period, so their
+// instalments shrink with the interest.
+type AmortizationMethod string
+
+const (
+	AnnuityAmortization AmortizationMethod = "annuity"
+	LinearAmortization  AmortizationMethod = "linear"
+)
+
+type LoanStatus string
+
+const (
+	ActiveLoan LoanStatus = "active"
+	RepaidLoan LoanStatus = "repaid"
+)
+
+type InstalmentStatus string
+
+const (
+	ScheduledInstalment InstalmentStatus = "scheduled"
+	PaidInstalment      InstalmentStatus = "paid"
+	// OverdueInstalment instalments are in arrears: collecting them failed on or after the due date.
+	OverdueInstalment InstalmentStatus = "overdue"
+)
+
+// MaxLoanTerm is the largest number of instalments a loan may be repaid in.
+const MaxLoanTerm = 600
+
+var (
+	ErrInvalidLoan  = errors.New("invalid loan")
+	ErrLoanNotFound = errors.New("loan not found")
+	ErrLoanAccount  = errors.New("loan accounts only move money through their loan")
+)
+
+// Loan lends Principal to the owner of RepaymentAccount, who pays it back with interest in Term
+// instalments collected from that account. The debt is kept on a loan account of its own.
+// Outstanding is the principal still owed and Arrears what is overdue, late fees included.
+type Loan struct {
+	ID               int64              `json:"id"`
+	AccountNumber    string             `json:"accountNumber"`
+	RepaymentAccount string             `json:"repaymentAccount"`
+	OwnerID          string             `json:"ownerId"`
+	Principal        Money              `json:"principal"`
+	AnnualRate       string             `json:"annualRate" example:"0.05"`
+	Term             int                `json:"term"`
+	Frequency        RepaymentFrequency `json:"frequency"`
+	Method           AmortizationMethod `json:"method"`
+	LateFee          Money              `json:"lateFee"`
+	Status           LoanStatus         `json:"status"`
+	Outstanding      Money              `json:"outstanding"`
+	Arrears          Money              `json:"arrears"`
+	DisbursementID   int64              `json:"disbursementId"`
+	Disbursed        time.Time          `json:"disbursed"`
+	Created          time.Time          `json:"created"`
+}
+
+// LoanInstalment is one repayment of a loan. Amount is the principal and interest part together;
+// a late fee is added once the instalment falls into arrears.
+type LoanInstalment struct {
+	ID            int64            `json:"id"`
+	LoanID        int64            `json:"loanId"`
+	Number        int              `json:"number"`
+	Due           time.Time        `json:"due"`
+	Principal     Money            `json:"principal"`
+	Interest      Money            `json:"interest"`
+	Amount        Money            `json:"amount"`
+	LateFee       Money            `json:"lateFee"`
+	Status        InstalmentStatus `json:"status"`
+	Attempts      int              `json:"attempts"`
+	NextAttempt   time.Time        `json:"nextAttempt"`
+	TransactionID int64            `json:"transactionId,omitempty"`
+	Paid          *time.Time       `json:"paid,omitempty"`
+}
+
+func ParseRepaymentFrequency(s string) (RepaymentFrequency, error) {
+	switch f := RepaymentFrequency(s); f {
+	case WeeklyRepayment, MonthlyRepayment, QuarterlyRepayment:
+		return f, nil
+	}
+	return "", errors.Wrapf(ErrInvalidLoan, "unknown repayment frequency %q", s)
+}
+
+func ParseAmortizationMethod(s string) (AmortizationMethod, error) {
+	switch m := AmortizationMethod(s); m {
+	case AnnuityAmortization, LinearAmortization:
+		return m, nil
+	}
+	return "", errors.Wrapf(ErrInvalidLoan, "unknown amortization method %q", s)
+}
+
+// periodsPerYear is how many instalments fall in a year, which turns the annual rate into the
+// rate of one period.
+func (f RepaymentFrequency) periodsPerYear() int64 {
+	switch f {
+	case WeeklyRepayment:
+		return 52
+	case QuarterlyRepayment:
+		return 4
+	}
+	return 12
+}
+
+// due returns the due date of the n-th instalment of a loan disbursed on start. Monthly and
+// quarterly instalments fall on the day of the month of the disbursement, or the last day of
+// shorter months.
+func (f RepaymentFrequency) due(start time.Time, n int) time.Time {
+	switch f {
+	case WeeklyRepayment:
+		return start.AddDate(0, 0, 7*n)
+	case QuarterlyRepayment:
+		return monthDay(start.Year(), start.Month()+time.Month(3*n), start.Day())
+	}
+	return monthDay(start.Year(), start.Month()+time.Month(n), start.Day())
+}
+
+func NewLoan(ownerID string, repaymentAccount string, principal Money, annualRate string, term int,
+	frequency RepaymentFrequency, method AmortizationMethod, lateFee Money) (*Loan, error) {
+	if !principal.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "principal must be positive")
+	}
+	if _, err := ParseInterestRate(annualRate); err != nil {
+		return nil, err
+	}
+	if term < 1 || term > MaxLoanTerm {
+		return nil, errors.Wrapf(ErrInvalidLoan, "term must be between 1 and %d instalments", MaxLoanTerm)
+	}
+	if lateFee.Currency != principal.Currency {
+		return nil, errors.Wrapf(ErrCurrencyMismatch, "late fee must be in %s", principal.Currency)
+	}
+	if lateFee.IsNegative() {
+		return nil, errors.Wrap(ErrInvalidAmount, "late fee must not be negative")
+	}
+	now := time.Now()
+	return &Loan{
+		RepaymentAccount: repaymentAccount,
+		OwnerID:          ownerID,
+		Principal:        principal,
+		AnnualRate:       strings.TrimSpace(annualRate),
+		Term:             term,
+		Frequency:        frequency,
+		Method:           method,
+		LateFee:          lateFee,
+		Status:           ActiveLoan,
+		Outstanding:      principal,
+		Arrears:          Zero(principal.Currency),
+		Disbursed:        now,
+		Created:          now,
+	}, nil
+}
+
+// Schedule computes the instalments of the loan. Interest is charged on the outstanding principal
+// at the annual rate divided by the number of periods in a year and rounded every period; the
+// last instalment repays whatever principal is left, so the parts always add up to the principal.
+func (l *Loan) Schedule() ([]*LoanInstalment, error) {
+	annual, err := ParseInterestRate(l.AnnualRate)
+	if err != nil {
+		return nil, err
+	}
+	rate := new(big.Rat).Quo(annual, big.NewRat(l.Frequency.periodsPerYear(), 1))
+
+	var annuity Money
+	var linear []Money
+	if l.Method == LinearAmortization {
+		ratios := make([]int, l.Term)
+		for i := range ratios {
+			ratios[i] = 1
+		}
+		if linear, err = l.Principal.Allocate(ratios...); err != nil {
+			return nil, err
+		}
+	} else if annuity, err = annuityPayment(l.Principal, rate, l.Term); err != nil {
+		return nil, err
+	}
+
+	start := Day(l.Disbursed)
+	outstanding := l.Principal
+	instalments := make([]*LoanInstalment, 0, l.Term)
+	for n := 1; n <= l.Term; n++ {
+		interest, err := MoneyFromRat(new(big.Rat).Mul(outstanding.Rat(), rate), l.Principal.Currency)
+		if err != nil {
+			return nil, err
+		}
+		principal := outstanding
+		switch {
+		case n == l.Term:
+		case l.Method == LinearAmortization:
+			principal = linear[n-1]
+		default:
+			if principal, err = annuity.Sub(interest); err != nil {
+				return nil, err
+			}
+		}
+		if principal.IsNegative() || principal.Amount > outstanding.Amount {
+			return nil, errors.Wrap(ErrInvalidLoan, "the instalments don't repay the principal")
+		}
+		if outstanding, err = outstanding.Sub(principal); err != nil {
+			return nil, err
+		}
+		amount, err := principal.Add(interest)
+		if err != nil {
+			return nil, err
+		}
+		due := l.Frequency.due(start, n)
+		instalments = append(instalments, &LoanInstalment{
+			LoanID:      l.ID,
+			Number:      n,
+			Due:         due,
+			Principal:   principal,
+			Interest:    interest,
+			Amount:      amount,
+			LateFee:     Zero(l.Principal.Currency),
+			Status:      ScheduledInstalment,
+			NextAttempt: due,
+		})
+	}
+	return instalments, nil
+}
+
+// annuityPayment is the fixed instalment repaying principal over n periods at the periodic rate:
+// principal * rate / (1 - (1 + rate)^-n), rounded to the minor unit.
+func annuityPayment(principal Money, rate *big.Rat, n int) (Money, error) {
+	if rate.Sign() == 0 {
+		return MoneyFromRat(new(big.Rat).Quo(principal.Rat(), big.NewRat(int64(n), 1)), principal.Currency)
+	}
+	growth := new(big.Rat).Add(big.NewRat(1, 1), rate)
+	compounded := big.NewRat(1, 1)
+	for i := 0; i < n; i++ {
+		compounded.Mul(compounded, growth)
+	}
+	// principal * rate * (1+rate)^n / ((1+rate)^n - 1)
+	payment := new(big.Rat).Mul(principal.Rat(), rate)
+	payment.Mul(payment, compounded)
+	payment.Quo(payment, new(big.Rat).Sub(compounded, big.NewRat(1, 1)))
+	return MoneyFromRat(payment, principal.Currency)
+}
+
+// Summarise fills in the outstanding principal from the balance of the loan account and the
+// arrears from the instalments.
+func (l *Loan) Summarise(balance Money, instalments []*LoanInstalment) error {
+	l.Outstanding = balance.Neg()
+	l.Arrears = Zero(l.Principal.Currency)
+	for _, i := range instalments {
+		if i.Status != OverdueInstalment {
+			continue
+		}
+		owed, err := i.Owed()
+		if err != nil {
+			return err
+		}
+		if l.Arrears, err = l.Arrears.Add(owed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Owed is what collecting the instalment takes from the repayment account.
+func (i *LoanInstalment) Owed() (Money, error) {
+	return i.Amount.Add(i.LateFee)
+}
+
+// RecordFailure notes a failed collection. The first failure puts the instalment into arrears and
+// charges the late fee; collection is retried after delay until it succeeds.
+func (i *LoanInstalment) RecordFailure(now time.Time, delay time.Duration, lateFee Money) {
+	i.Attempts++
+	if i.Status == ScheduledInstalment {
+		i.Status = OverdueInstalment
+		i.LateFee = lateFee
+	}
+	i.NextAttempt = now.Add(delay)
+}
+
+// RecordPayment marks the instalment paid by the given transaction.
+func (i *LoanInstalment) RecordPayment(now time.Time, transactionID int64) {
+	i.Attempts++
+	i.Status = PaidInstalment
+	i.TransactionID = transactionID
+	i.Paid = &now
+}
+
+// NewLoanDisbursement pays the principal out of the loan account, which goes negative by the
+// amount owed, into the repayment account.
+func NewLoanDisbursement(l *Loan) *Transaction {
+	t := NewTransaction(LoanDisbursementTransaction, "Loan "+l.AccountNumber, "Loan disbursement")
+	return t.Debit(l.AccountNumber, l.Principal).Credit(l.RepaymentAccount, l.Principal)
+}
+
+// NewLoanRepayment collects an instalment from the repayment account: the principal part goes to
+// the loan account, the interest and any late fee to the bank.
+func NewLoanRepayment(l *Loan, i *LoanInstalment, interestIncomeAccount string, feeIncomeAccount string) (*Transaction, error) {
+	owed, err := i.Owed()
+	if err != nil {
+		return nil, err
+	}
+	t := NewTransaction(LoanRepaymentTransaction, "Loan "+l.AccountNumber, "Loan instalment "+i.Due.Format("2006-01-02"))
+	t.Debit(l.RepaymentAccount, owed)
+	if i.Principal.IsPositive() {
+		t.Credit(l.AccountNumber, i.Principal)
+	}
+	if i.Interest.IsPositive() {
+		t.Credit(interestIncomeAccount, i.Interest)
+	}
+	if i.LateFee.IsPositive() {
+		t.Credit(feeIncomeAccount, i.LateFee)
+	}
+	return t, nil
+}
@@ -0,0 +1,294 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 alphabetic currency code.
+type Currency string
+
+const (
+	EUR Currency = "EUR"
+	USD Currency = "USD"
+	GBP Currency = "GBP"
+	CHF Currency = "CHF"
+	JPY Currency = "JPY"
+)
+
+// DefaultCurrency is used for accounts created without an explicit currency.
+const DefaultCurrency = EUR
+
+// minorUnits holds the number of decimal places (ISO 4217 exponent) of every supported currency.
+var minorUnits = map[Currency]int{
+	EUR: 2,
+	USD: 2,
+	GBP: 2,
+	CHF: 2,
+	JPY: 0,
+}
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrCurrencyMismatch    = errors.New("currency mismatch")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrAmountOverflow      = errors.New("amount overflow")
+)
+
+func ParseCurrency(s string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(s)))
+	if _, ok := minorUnits[c]; !ok {
+		return "", errors.Wrap(ErrUnsupportedCurrency, s)
+	}
+	return c, nil
+}
+
+// MinorUnits returns the number of decimal places used by the currency.
+func (c Currency) MinorUnits() int {
+	return minorUnits[c]
+}
+
+// Money is an exact monetary amount expressed in minor units (e.g. cents) of a currency.
+type Money struct {
+	Amount   int64    `json:"amount" swaggertype:"string" example:"125.50"`
+	Currency Currency `json:"currency" swaggertype:"string" example:"EUR"`
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func Zero(currency Currency) Money {
+	return Money{Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "-12.50" into an exact amount of the given currency.
+// Amounts with more decimal places than the currency allows are rejected rather than rounded.
+func ParseMoney(s string, currency Currency) (Money, error) {
+	if _, ok := minorUnits[currency]; !ok {
+		return Money{}, errors.Wrap(ErrUnsupportedCurrency, string(currency))
+	}
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	exp := currency.MinorUnits()
+	if whole == "" || !isDigits(whole) || (hasPoint && (frac == "" || !isDigits(frac))) || len(frac) > exp {
+		return Money{}, errors.Wrapf(ErrInvalidAmount, "%q", s)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, errors.Wrapf(ErrAmountOverflow, "%q", s)
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount as a plain decimal string without the currency code.
+func (m Money) String() string {
+	exp := m.Currency.MinorUnits()
+	sign := ""
+	abs := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		abs = uint64(-(m.Amount + 1)) + 1
+	}
+	digits := strconv.FormatUint(abs, 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+func (m Money) Abs() Money {
+	if m.Amount < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return errors.Wrapf(ErrCurrencyMismatch, "%s and %s", m.Currency, o.Currency)
+	}
+	return nil
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Amount == math.MinInt64 {
+		return Money{}, ErrAmountOverflow
+	}
+	return m.Add(o.Neg())
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Allocate splits the amount between the given ratios without losing minor units.
+// Remainders are handed out one unit at a time, starting with the first share.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.Wrap(ErrInvalidAmount, "no ratios to allocate to")
+	}
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.Wrap(ErrInvalidAmount, "negative allocation ratio")
+		}
+		total += int64(r)
+	}
+	if total == 0 {
+		return nil, errors.Wrap(ErrInvalidAmount, "allocation ratios sum to zero")
+	}
+
+	shares := make([]Money, len(ratios))
+	remainder := m.Amount
+	for i, r := range ratios {
+		share := m.Amount / total * int64(r)
+		share += m.Amount % total * int64(r) / total
+		shares[i] = Money{Amount: share, Currency: m.Currency}
+		remainder -= share
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+		shares[i].Amount += step
+		remainder -= step
+	}
+	return shares, nil
+}
+
+// Rat returns the amount in major units (e.g. euros) as an exact fraction.
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(m.Currency.MinorUnits()))
+}
+
+// MoneyFromRat converts an amount in major units to Money, rounding half to even
+// to the minor unit of the currency.
+func MoneyFromRat(r *big.Rat, currency Currency) (Money, error) {
+	if _, ok := minorUnits[currency]; !ok {
+		return Money{}, errors.Wrap(ErrUnsupportedCurrency, string(currency))
+	}
+	minor := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(currency.MinorUnits())))
+	amount, err := roundHalfEven(minor)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func roundHalfEven(r *big.Rat) (int64, error) {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	// Compare twice the remainder with the denominator to find out which way to round.
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	switch twice.Cmp(r.Denom()) {
+	case 1:
+		quo.Add(quo, big.NewInt(int64(r.Sign())))
+	case 0:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(int64(r.Sign())))
+		}
+	}
+	if !quo.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+	return quo.Int64(), nil
+}
+
+type moneyJSON struct {
+	Amount   string   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string so clients never see floating point values.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	currency, err := ParseCurrency(string(raw.Currency))
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(raw.Amount, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func (m Money) Format() string {
+	return fmt.Sprintf("%s %s", m.String(), m.Currency)
+}
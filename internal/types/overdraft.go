@@ -0,0 +1,25 @@
+package types
+
+import "github.com/pkg/errors"
+
+var ErrInvalidOverdraftLimit = errors.New("invalid overdraft limit")
+
+// SetOverdraftLimit lets the balance go down to -limit; a zero limit removes the overdraft.
+// Lowering the limit below what is already drawn is allowed: the account then takes no further
+// debits until it is back within the limit.
+func (a *Account) SetOverdraftLimit(limit Money) error {
+	if !a.Payable() {
+		return errors.Wrap(ErrInvalidOverdraftLimit, "only current and savings accounts have an overdraft")
+	}
+	if a.Status == ClosedAccount {
+		return errors.Wrapf(ErrAccountNotActive, "account %s is %s", a.AccountNumber, a.Status)
+	}
+	if limit.Currency != a.Balance.Currency {
+		return errors.Wrapf(ErrCurrencyMismatch, "account %s is held in %s", a.AccountNumber, a.Balance.Currency)
+	}
+	if limit.IsNegative() {
+		return errors.Wrap(ErrInvalidOverdraftLimit, "limit must not be negative")
+	}
+	a.OverdraftLimit = limit
+	return nil
+}
@@ -0,0 +1,71 @@
+package types
+
+import "time"
+
+type ReconciliationStatus string
+
+const (
+	RunningReconciliation   ReconciliationStatus = "running"
+	CompletedReconciliation ReconciliationStatus = "completed"
+	FailedReconciliation    ReconciliationStatus = "failed"
+)
+
+// ReconciliationRun is one pass comparing the stored balance of every account with the balance
+// its ledger entries add up to. Accounts counts the accounts checked so far.
+type ReconciliationRun struct {
+	ID         int64                `json:"id"`
+	Status     ReconciliationStatus `json:"status"`
+	Accounts   int                  `json:"accounts"`
+	Mismatches []*BalanceMismatch   `json:"mismatches"`
+	Error      string               `json:"error,omitempty"`
+	Started    time.Time            `json:"started"`
+	Finished   *time.Time           `json:"finished,omitempty"`
+}
+
+func NewReconciliationRun(now time.Time) *ReconciliationRun {
+	return &ReconciliationRun{
+		Status:     RunningReconciliation,
+		Mismatches: []*BalanceMismatch{},
+		Started:    now,
+	}
+}
+
+// Finish ends the run, as failed if err is not nil.
+func (r *ReconciliationRun) Finish(now time.Time, err error) {
+	r.Status = CompletedReconciliation
+	if err != nil {
+		r.Status = FailedReconciliation
+		r.Error = err.Error()
+	}
+	r.Finished = &now
+}
+
+// BalanceMismatch is an account whose stored balance differs from the sum of its ledger entries.
+// Difference is what the stored balance exceeds the computed one by.
+type BalanceMismatch struct {
+	RunID         int64     `json:"runId"`
+	AccountNumber string    `json:"accountNumber"`
+	Stored        Money     `json:"stored"`
+	Computed      Money     `json:"computed"`
+	Difference    Money     `json:"difference"`
+	Checked       time.Time `json:"checked"`
+}
+
+// NewBalanceMismatch compares the balances and returns nil when they agree.
+func NewBalanceMismatch(runID int64, accountNumber string, stored Money, computed Money, checked time.Time) (*BalanceMismatch, error) {
+	difference, err := stored.Sub(computed)
+	if err != nil {
+		return nil, err
+	}
+	if difference.IsZero() {
+		return nil, nil
+	}
+	return &BalanceMismatch{
+		RunID:         runID,
+		AccountNumber: accountNumber,
+		Stored:        stored,
+		Computed:      computed,
+		Difference:    difference,
+		Checked:       checked,
+	}, nil
+}
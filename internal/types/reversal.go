@@ -0,0 +1,149 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"strconv"
+)
+
+var (
+	ErrAlreadyReversed         = errors.New("transaction has already been reversed")
+	ErrReversalExceedsOriginal = errors.New("reversal exceeds what is left of the original transaction")
+	ErrCannotReverse           = errors.New("transaction cannot be reversed")
+)
+
+// CheckReversible fails for transactions whose effects are tracked outside the ledger. Reversing
+// them directly would leave that state behind, so they are undone by their own operations.
+func (t TransactionType) CheckReversible() error {
+	switch t {
+	case FeeTransaction, MaintenanceFeeTransaction:
+		return errors.Wrap(ErrCannotReverse, "fees are refunded by waiving them")
+	case HoldCaptureTransaction:
+		return errors.Wrap(ErrCannotReverse, "captured holds are refunded with a new payment")
+	case LoanDisbursementTransaction, LoanRepaymentTransaction:
+		return errors.Wrap(ErrCannotReverse, "loan postings follow the loan's schedule")
+	case TermDepositFundingTransaction, TermDepositMaturityTransaction, TermDepositWithdrawalTransaction:
+		return errors.Wrap(ErrCannotReverse, "term deposit postings follow the deposit's own operations")
+	}
+	return nil
+}
+
+// NewReversal builds the compensating transaction for original. Entry i of a reversal always
+// offsets entry i of the original, which lets previous reversals be netted off. Without an
+// amount everything not yet reversed is reversed; a partial amount is given in the currency of
+// the first entry and every entry is reversed in the same proportion.
+func NewReversal(original *Transaction, previous []*Transaction, amount *Money, reason string) (*Transaction, error) {
+	if original.Type == ReversalTransaction {
+		return nil, errors.Wrap(ErrCannotReverse, "reversals can't be reversed")
+	}
+	if len(original.Entries) == 0 {
+		return nil, errors.Wrap(ErrCannotReverse, "transaction has no entries")
+	}
+
+	remaining := make([]Money, len(original.Entries))
+	for i, e := range original.Entries {
+		remaining[i] = e.Amount
+	}
+	for _, r := range previous {
+		if len(r.Entries) != len(original.Entries) {
+			return nil, errors.Errorf("reversal %d does not mirror transaction %d", r.ID, original.ID)
+		}
+		for i, e := range r.Entries {
+			var err error
+			if remaining[i], err = remaining[i].Sub(e.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	amounts := remaining
+	if amount != nil {
+		first := original.Entries[0].Amount
+		if amount.Currency != first.Currency {
+			return nil, errors.Wrapf(ErrCurrencyMismatch, "reversal amount must be in %s", first.Currency)
+		}
+		if !amount.IsPositive() {
+			return nil, errors.Wrap(ErrInvalidAmount, "reversal amount must be positive")
+		}
+		var err error
+		if amounts, err = scaleEntries(original.Entries, *amount); err != nil {
+			return nil, err
+		}
+		for i := range amounts {
+			if cmp, _ := amounts[i].Cmp(remaining[i]); cmp > 0 {
+				return nil, errors.Wrapf(ErrReversalExceedsOriginal, "%s left to reverse", remaining[0].Format())
+			}
+			if !amounts[i].IsPositive() {
+				return nil, errors.Wrapf(ErrInvalidAmount, "%s is too little to reverse every entry", amount.Format())
+			}
+		}
+	} else if remaining[0].IsZero() {
+		return nil, errors.Wrapf(ErrAlreadyReversed, "transaction %d", original.ID)
+	}
+
+	description := "Reversal of transaction " + strconv.FormatInt(original.ID, 10)
+	if reason != "" {
+		description += ": " + reason
+	}
+	t := NewTransaction(ReversalTransaction, original.Reference, description)
+	t.ReversalOf = original.ID
+	for i, e := range original.Entries {
+		if e.Direction == Debit {
+			t.Credit(e.AccountNumber, amounts[i])
+		} else {
+			t.Debit(e.AccountNumber, amounts[i])
+		}
+	}
+	if err := t.Validate(); err != nil {
+		return nil, errors.Wrapf(ErrCannotReverse, "reversal: %v", err)
+	}
+	return t, nil
+}
+
+// scaleEntries splits a partial reversal amount, given in the currency of the first entry, across
+// the entries in proportion to their amounts. The debits and the credits of each currency are
+// allocated the same total, so the reversal balances in every currency however shares are rounded.
+func scaleEntries(entries []LedgerEntry, amount Money) ([]Money, error) {
+	ratio := new(big.Rat).SetFrac64(amount.Amount, entries[0].Amount.Amount)
+
+	type side struct {
+		currency  Currency
+		direction EntryDirection
+	}
+	var sides []side
+	indexes := map[side][]int{}
+	sums := map[side]int64{}
+	for i, e := range entries {
+		k := side{e.Amount.Currency, e.Direction}
+		if _, ok := indexes[k]; !ok {
+			sides = append(sides, k)
+		}
+		indexes[k] = append(indexes[k], i)
+		sums[k] += e.Amount.Amount
+	}
+
+	totals := map[Currency]Money{}
+	amounts := make([]Money, len(entries))
+	for _, k := range sides {
+		total, ok := totals[k.currency]
+		if !ok {
+			var err error
+			if total, err = MoneyFromRat(new(big.Rat).Mul(NewMoney(sums[k], k.currency).Rat(), ratio), k.currency); err != nil {
+				return nil, err
+			}
+			totals[k.currency] = total
+		}
+		ratios := make([]int, len(indexes[k]))
+		for j, i := range indexes[k] {
+			ratios[j] = int(entries[i].Amount.Amount)
+		}
+		shares, err := total.Allocate(ratios...)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range indexes[k] {
+			amounts[i] = shares[j]
+		}
+	}
+	return amounts, nil
+}
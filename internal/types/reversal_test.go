@@ -0,0 +1,36 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestPartialReversalBalances(t *testing.T) {
+	// A transfer of 10.00 with two fees of 0.03 taken from the same account in one posting.
+	original := NewTransaction(TransferTransaction, "INV-1", "")
+	original.ID = 7
+	original.Debit("100", NewMoney(1006, "EUR")).
+		Credit("200", NewMoney(1000, "EUR")).
+		Credit("900", NewMoney(3, "EUR")).
+		Credit("901", NewMoney(3, "EUR"))
+
+	for _, amount := range []int64{500, 503, 700, 920, 1005} {
+		m := NewMoney(amount, "EUR")
+		reversal, err := NewReversal(original, nil, &m, "")
+		if err != nil {
+			t.Fatalf("reversing %s: %v", m.Format(), err)
+		}
+		if err := reversal.Validate(); err != nil {
+			t.Errorf("reversing %s: %v", m.Format(), err)
+		}
+		if got := reversal.Entries[0].Amount; got != m {
+			t.Errorf("reversing %s credits %s back", m.Format(), got.Format())
+		}
+	}
+
+	// Too little to take a share of the fees.
+	m := NewMoney(100, "EUR")
+	if _, err := NewReversal(original, nil, &m, ""); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("reversing %s: %v, want %v", m.Format(), err, ErrInvalidAmount)
+	}
+}
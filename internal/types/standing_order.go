@@ -0,0 +1,181 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+)
+
+type StandingOrderStatus string
+
+const (
+	ActiveStandingOrder    StandingOrderStatus = "active"
+	CompletedStandingOrder StandingOrderStatus = "completed"
+	CancelledStandingOrder StandingOrderStatus = "cancelled"
+)
+
+type ExecutionStatus string
+
+const (
+	ExecutionSucceeded ExecutionStatus = "succeeded"
+	// ExecutionRetrying marks a failed attempt that will be tried again.
+	ExecutionRetrying ExecutionStatus = "retrying"
+	// ExecutionFailed marks a payment that was given up on; the order moves on to its next due date.
+	ExecutionFailed ExecutionStatus = "failed"
+)
+
+var (
+	ErrInvalidSchedule         = errors.New("invalid standing order schedule")
+	ErrStandingOrderNotActive  = errors.New("standing order is no longer active")
+	ErrStandingOrderNotFound   = errors.New("standing order not found")
+	ErrStandingOrderNotAllowed = errors.New("source account does not belong to the standing order owner")
+)
+
+// StandingOrder transfers a fixed amount between two accounts on a recurring schedule.
+// NextRun is the due date of the next payment and NextAttempt when it will be tried,
+// which is later than NextRun while a failed payment waits for a retry.
+type StandingOrder struct {
+	ID          int64               `json:"id"`
+	OwnerID     string              `json:"ownerId"`
+	FromAccount string              `json:"fromAccount"`
+	ToAccount   string              `json:"toAccount"`
+	Amount      Money               `json:"amount"`
+	Reference   string              `json:"reference"`
+	Frequency   Frequency           `json:"frequency"`
+	DayOfMonth  int                 `json:"dayOfMonth,omitempty"`
+	StartDate   time.Time           `json:"startDate"`
+	EndDate     *time.Time          `json:"endDate,omitempty"`
+	NextRun     time.Time           `json:"nextRun"`
+	NextAttempt time.Time           `json:"nextAttempt"`
+	Attempts    int                 `json:"attempts"`
+	Status      StandingOrderStatus `json:"status"`
+	Created     time.Time           `json:"created"`
+}
+
+// StandingOrderExecution records the outcome of one attempt to pay a standing order.
+type StandingOrderExecution struct {
+	ID         int64           `json:"id"`
+	OrderID    int64           `json:"orderId"`
+	Due        time.Time       `json:"due"`
+	Attempt    int             `json:"attempt"`
+	Status     ExecutionStatus `json:"status"`
+	TransferID int64           `json:"transferId,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Created    time.Time       `json:"created"`
+}
+
+// RetryPolicy controls how often a failed payment is retried before it is given up on.
+type RetryPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+}
+
+func ParseFrequency(s string) (Frequency, error) {
+	switch f := Frequency(s); f {
+	case Daily, Weekly, Monthly:
+		return f, nil
+	}
+	return "", errors.Wrapf(ErrInvalidSchedule, "unknown frequency %q", s)
+}
+
+// NewStandingOrder schedules the first payment on the first due date on or after startDate.
+// dayOfMonth is required for monthly orders; in shorter months the payment falls on the last day.
+func NewStandingOrder(ownerID string, from string, to string, amount Money, reference string,
+	frequency Frequency, dayOfMonth int, startDate time.Time, endDate *time.Time) (*StandingOrder, error) {
+	if from == to {
+		return nil, ErrSameAccount
+	}
+	if !amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "standing order amount must be positive")
+	}
+	if frequency == Monthly && (dayOfMonth < 1 || dayOfMonth > 31) {
+		return nil, errors.Wrap(ErrInvalidSchedule, "monthly orders need a day of month between 1 and 31")
+	}
+	if frequency != Monthly {
+		dayOfMonth = 0
+	}
+
+	order := &StandingOrder{
+		OwnerID:     ownerID,
+		FromAccount: from,
+		ToAccount:   to,
+		Amount:      amount,
+		Reference:   reference,
+		Frequency:   frequency,
+		DayOfMonth:  dayOfMonth,
+		StartDate:   Day(startDate),
+		Status:      ActiveStandingOrder,
+		Created:     time.Now(),
+	}
+	order.NextRun = order.StartDate
+	if frequency == Monthly {
+		order.NextRun = monthDay(order.StartDate.Year(), order.StartDate.Month(), dayOfMonth)
+		if order.NextRun.Before(order.StartDate) {
+			order.NextRun = monthDay(order.StartDate.Year(), order.StartDate.Month()+1, dayOfMonth)
+		}
+	}
+	order.NextAttempt = order.NextRun
+
+	if endDate != nil {
+		end := Day(*endDate)
+		if end.Before(order.NextRun) {
+			return nil, errors.Wrap(ErrInvalidSchedule, "end date is before the first payment")
+		}
+		order.EndDate = &end
+	}
+	return order, nil
+}
+
+// monthDay returns the given day of a month, or the last day of the month if it is shorter.
+func monthDay(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Advance moves the order on to its next due date, completing it once the end date is passed.
+func (o *StandingOrder) Advance() {
+	switch o.Frequency {
+	case Daily:
+		o.NextRun = o.NextRun.AddDate(0, 0, 1)
+	case Weekly:
+		o.NextRun = o.NextRun.AddDate(0, 0, 7)
+	case Monthly:
+		o.NextRun = monthDay(o.NextRun.Year(), o.NextRun.Month()+1, o.DayOfMonth)
+	}
+	o.NextAttempt = o.NextRun
+	o.Attempts = 0
+	if o.EndDate != nil && o.NextRun.After(*o.EndDate) {
+		o.Status = CompletedStandingOrder
+	}
+}
+
+// RecordFailure schedules a retry of the current payment, or gives up on it once the policy's
+// attempts are used up. It returns the status of the failed execution.
+func (o *StandingOrder) RecordFailure(now time.Time, policy RetryPolicy) ExecutionStatus {
+	o.Attempts++
+	if o.Attempts < policy.MaxAttempts {
+		o.NextAttempt = now.Add(policy.Delay)
+		return ExecutionRetrying
+	}
+	o.Advance()
+	return ExecutionFailed
+}
+
+// Cancel stops all future payments of the order.
+func (o *StandingOrder) Cancel() error {
+	if o.Status != ActiveStandingOrder {
+		return ErrStandingOrderNotActive
+	}
+	o.Status = CancelledStandingOrder
+	return nil
+}
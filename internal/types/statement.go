@@ -0,0 +1,72 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+var ErrInvalidStatementPeriod = errors.New("invalid statement period")
+
+// Movement is a ledger entry on a statement, together with the transaction it belongs to
+// and the account balance right after it was booked.
+type Movement struct {
+	EntryID       int64           `json:"entryId"`
+	TransactionID int64           `json:"transactionId"`
+	Type          TransactionType `json:"type"`
+	Reference     string          `json:"reference"`
+	Description   string          `json:"description"`
+	ReversalOf    int64           `json:"reversalOf,omitempty"`
+	Amount        Money           `json:"amount"`
+	Balance       Money           `json:"balance"`
+	Booked        time.Time       `json:"booked"`
+}
+
+// Statement lists the movements of an account booked in the half-open period [From, To).
+type Statement struct {
+	AccountNumber  string     `json:"accountNumber"`
+	BankID         string     `json:"bankId"`
+	Currency       Currency   `json:"currency"`
+	From           time.Time  `json:"from"`
+	To             time.Time  `json:"to"`
+	OpeningBalance Money      `json:"openingBalance"`
+	ClosingBalance Money      `json:"closingBalance"`
+	Movements      []Movement `json:"movements"`
+	Generated      time.Time  `json:"generated"`
+}
+
+// NewStatement builds a statement from the balance at the start of the period and the movements
+// booked in it, ordered oldest first. Movement amounts are signed; running balances are filled in.
+func NewStatement(account *Account, bankID string, from time.Time, to time.Time, opening Money, movements []Movement) (*Statement, error) {
+	if !from.Before(to) {
+		return nil, errors.Wrap(ErrInvalidStatementPeriod, "start must be before end")
+	}
+	balance := opening
+	for i := range movements {
+		var err error
+		if balance, err = balance.Add(movements[i].Amount); err != nil {
+			return nil, err
+		}
+		movements[i].Balance = balance
+	}
+	if movements == nil {
+		movements = []Movement{}
+	}
+	return &Statement{
+		AccountNumber:  account.AccountNumber,
+		BankID:         bankID,
+		Currency:       account.Balance.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ClosingBalance: balance,
+		Movements:      movements,
+		Generated:      time.Now(),
+	}, nil
+}
+
+// HistoricalBalance is the balance of an account as implied by the entries booked before At.
+type HistoricalBalance struct {
+	AccountNumber string    `json:"accountNumber"`
+	Balance       Money     `json:"balance"`
+	At            time.Time `json:"at"`
+}
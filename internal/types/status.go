@@ -0,0 +1,67 @@
+package types
+
+import "github.com/pkg/errors"
+
+type AccountStatus string
+
+const (
+	ActiveAccount  AccountStatus = "active"
+	FrozenAccount  AccountStatus = "frozen"
+	DormantAccount AccountStatus = "dormant"
+	ClosedAccount  AccountStatus = "closed"
+)
+
+var (
+	ErrInvalidAccountStatus     = errors.New("invalid account status")
+	ErrInvalidStatusTransition  = errors.New("account status transition not allowed")
+	ErrAccountNotActive         = errors.New("account is not active")
+	ErrAccountNotEmpty          = errors.New("account balance must be zero or swept to another account before closing")
+	ErrAccountHasHolds          = errors.New("account holds must be captured or released before closing")
+	ErrUserDeactivated          = errors.New("user is deactivated")
+	ErrOpenAccountsPreventClose = errors.New("user still has accounts that cannot be closed")
+)
+
+// accountStatusTransitions lists the statuses each status may move to. Closed is final.
+var accountStatusTransitions = map[AccountStatus][]AccountStatus{
+	ActiveAccount:  {FrozenAccount, DormantAccount, ClosedAccount},
+	FrozenAccount:  {ActiveAccount, ClosedAccount},
+	DormantAccount: {ActiveAccount, FrozenAccount, ClosedAccount},
+	ClosedAccount:  {},
+}
+
+func ParseAccountStatus(s string) (AccountStatus, error) {
+	status := AccountStatus(s)
+	if _, ok := accountStatusTransitions[status]; !ok {
+		return "", errors.Wrapf(ErrInvalidAccountStatus, "%q", s)
+	}
+	return status, nil
+}
+
+func (s AccountStatus) CanTransitionTo(next AccountStatus) bool {
+	for _, allowed := range accountStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition reports whether the account may move to the given status.
+func (a *Account) CheckTransition(next AccountStatus) error {
+	if !a.Status.CanTransitionTo(next) {
+		return errors.Wrapf(ErrInvalidStatusTransition, "%s -> %s", a.Status, next)
+	}
+	if next == ClosedAccount && !a.Balance.IsZero() {
+		return errors.Wrapf(ErrAccountNotEmpty, "balance %s", a.Balance.Format())
+	}
+	return nil
+}
+
+// CheckCanPost reports whether money may be booked on the account. Only active accounts
+// take part in postings; internal bank accounts are always open.
+func (a *Account) CheckCanPost() error {
+	if a.Type == InternalAccount || a.Status == ActiveAccount {
+		return nil
+	}
+	return errors.Wrapf(ErrAccountNotActive, "account %s is %s", a.AccountNumber, a.Status)
+}
@@ -0,0 +1,187 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// TermDepositAccount accounts hold the money of a term deposit. They are funded from the linked
+// account when the deposit is opened and only pay out at maturity or on early withdrawal.
+const TermDepositAccount AccountType = "term_deposit"
+
+type TermDepositStatus string
+
+const (
+	ActiveTermDeposit TermDepositStatus = "active"
+	// MaturedTermDeposit deposits have been paid back with their interest at maturity.
+	MaturedTermDeposit TermDepositStatus = "matured"
+	// WithdrawnTermDeposit deposits were paid back before maturity, less the penalty.
+	WithdrawnTermDeposit TermDepositStatus = "withdrawn"
+)
+
+// MaxDepositTerm is the longest term of a deposit in months.
+const MaxDepositTerm = 120
+
+var (
+	ErrInvalidTermDeposit   = errors.New("invalid term deposit")
+	ErrTermDepositNotFound  = errors.New("term deposit not found")
+	ErrTermDepositNotActive = errors.New("term deposit is no longer active")
+	ErrTermDepositLocked    = errors.New("term deposit accounts are locked until maturity")
+)
+
+// TermDeposit locks Principal away on a term deposit account from Start until Maturity at a fixed
+// AnnualRate. The interest (ACT/365, simple) is paid at maturity together with the principal into
+// LinkedAccount. Deposits set to roll over keep the money instead: the interest is added to the
+// principal and a new term of the same length starts at the old maturity date. Withdrawing early
+// forfeits the interest of the running term and PenaltyRate of the principal.
+type TermDeposit struct {
+	ID            int64             `json:"id"`
+	AccountNumber string            `json:"accountNumber"`
+	LinkedAccount string            `json:"linkedAccount"`
+	OwnerID       string            `json:"ownerId"`
+	Principal     Money             `json:"principal"`
+	AnnualRate    string            `json:"annualRate" example:"0.03"`
+	Term          int               `json:"term"`
+	PenaltyRate   string            `json:"penaltyRate" example:"0.01"`
+	Rollover      bool              `json:"rollover"`
+	Status        TermDepositStatus `json:"status"`
+	Start         time.Time         `json:"start"`
+	Maturity      time.Time         `json:"maturity"`
+	Interest      Money             `json:"interest"`
+	Rollovers     int               `json:"rollovers"`
+	FundingID     int64             `json:"fundingId"`
+	PayoutID      int64             `json:"payoutId,omitempty"`
+	Created       time.Time         `json:"created"`
+	Closed        *time.Time        `json:"closed,omitempty"`
+}
+
+// NewTermDeposit opens a deposit of principal for term months starting today.
+func NewTermDeposit(ownerID string, linkedAccount string, principal Money, annualRate string, term int,
+	penaltyRate string, rollover bool) (*TermDeposit, error) {
+	if !principal.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "principal must be positive")
+	}
+	if _, err := ParseInterestRate(annualRate); err != nil {
+		return nil, err
+	}
+	if term < 1 || term > MaxDepositTerm {
+		return nil, errors.Wrapf(ErrInvalidTermDeposit, "term must be between 1 and %d months", MaxDepositTerm)
+	}
+	if _, err := ParseInterestRate(penaltyRate); err != nil {
+		return nil, errors.Wrapf(ErrInvalidTermDeposit, "penalty rate %q must be a fraction between 0 and 1", penaltyRate)
+	}
+	now := time.Now()
+	d := &TermDeposit{
+		LinkedAccount: linkedAccount,
+		OwnerID:       ownerID,
+		Principal:     principal,
+		AnnualRate:    strings.TrimSpace(annualRate),
+		Term:          term,
+		PenaltyRate:   strings.TrimSpace(penaltyRate),
+		Rollover:      rollover,
+		Status:        ActiveTermDeposit,
+		Created:       now,
+	}
+	return d, d.startTerm(Day(now))
+}
+
+// startTerm begins a term on start and fixes the interest due at its maturity.
+func (d *TermDeposit) startTerm(start time.Time) error {
+	d.Start = start
+	d.Maturity = monthDay(start.Year(), start.Month()+time.Month(d.Term), start.Day())
+	rate, err := ParseInterestRate(d.AnnualRate)
+	if err != nil {
+		return err
+	}
+	interest := new(big.Rat).Mul(d.Principal.Rat(), rate)
+	interest.Mul(interest, Actual365.YearFraction(d.Start, d.Maturity))
+	d.Interest, err = MoneyFromRat(interest, d.Principal.Currency)
+	return err
+}
+
+// Penalty is what withdrawing before maturity costs.
+func (d *TermDeposit) Penalty() (Money, error) {
+	rate, err := ParseInterestRate(d.PenaltyRate)
+	if err != nil {
+		return Money{}, err
+	}
+	return MoneyFromRat(new(big.Rat).Mul(d.Principal.Rat(), rate), d.Principal.Currency)
+}
+
+// CheckActive fails for deposits that have already been paid back.
+func (d *TermDeposit) CheckActive() error {
+	if d.Status != ActiveTermDeposit {
+		return errors.Wrapf(ErrTermDepositNotActive, "term deposit %d is %s", d.ID, d.Status)
+	}
+	return nil
+}
+
+// RollOver adds the interest of the term that matured to the principal and starts the next term.
+func (d *TermDeposit) RollOver() error {
+	principal, err := d.Principal.Add(d.Interest)
+	if err != nil {
+		return err
+	}
+	d.Principal = principal
+	d.Rollovers++
+	return d.startTerm(d.Maturity)
+}
+
+// Close marks the deposit paid back by the given transaction.
+func (d *TermDeposit) Close(status TermDepositStatus, now time.Time, payoutID int64) {
+	d.Status = status
+	d.PayoutID = payoutID
+	d.Closed = &now
+}
+
+// NewTermDepositFunding moves the principal from the linked account onto the deposit account.
+func NewTermDepositFunding(d *TermDeposit) *Transaction {
+	t := NewTransaction(TermDepositFundingTransaction, "Term deposit "+d.AccountNumber, "Term deposit until "+d.Maturity.Format("2006-01-02"))
+	return t.Debit(d.LinkedAccount, d.Principal).Credit(d.AccountNumber, d.Principal)
+}
+
+// NewTermDepositMaturity pays the fixed interest out of the interest expense account. Deposits that
+// roll over keep the interest on the deposit account; all others are paid back to the linked
+// account with their interest. It returns nil for a deposit rolling over without interest.
+func NewTermDepositMaturity(d *TermDeposit, expenseAccount string) *Transaction {
+	t := NewTransaction(TermDepositMaturityTransaction, "Term deposit "+d.AccountNumber, "Term deposit matured "+d.Maturity.Format("2006-01-02"))
+	if d.Interest.IsPositive() {
+		t.Debit(expenseAccount, d.Interest)
+		if d.Rollover {
+			t.Credit(d.AccountNumber, d.Interest)
+		} else {
+			t.Credit(d.LinkedAccount, d.Interest)
+		}
+	}
+	if !d.Rollover {
+		t.Debit(d.AccountNumber, d.Principal).Credit(d.LinkedAccount, d.Principal)
+	}
+	if len(t.Entries) == 0 {
+		return nil
+	}
+	return t
+}
+
+// NewTermDepositWithdrawal pays the principal back to the linked account before maturity, less the
+// penalty, which goes to the bank.
+func NewTermDepositWithdrawal(d *TermDeposit, feeIncomeAccount string) (*Transaction, error) {
+	penalty, err := d.Penalty()
+	if err != nil {
+		return nil, err
+	}
+	refund, err := d.Principal.Sub(penalty)
+	if err != nil {
+		return nil, err
+	}
+	t := NewTransaction(TermDepositWithdrawalTransaction, "Term deposit "+d.AccountNumber, "Early withdrawal of term deposit")
+	t.Debit(d.AccountNumber, d.Principal)
+	if refund.IsPositive() {
+		t.Credit(d.LinkedAccount, refund)
+	}
+	if penalty.IsPositive() {
+		t.Credit(feeIncomeAccount, penalty)
+	}
+	return t, nil
+}
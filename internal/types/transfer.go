@@ -0,0 +1,95 @@
+package types
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSameAccount       = errors.New("source and destination account must differ")
+)
+
+// Transfer moves money from one customer account to another in a single ledger transaction.
+// Amount is in the source account currency and ConvertedAmount in the destination account
+// currency; they only differ for cross-currency transfers, which also carry the applied rate.
+// Fees lists what the sender was charged for the transfer when it was made.
+type Transfer struct {
+	ID              int64     `json:"id"`
+	FromAccount     string    `json:"fromAccount"`
+	ToAccount       string    `json:"toAccount"`
+	Amount          Money     `json:"amount"`
+	ConvertedAmount Money     `json:"convertedAmount"`
+	ExchangeRate    string    `json:"exchangeRate,omitempty"`
+	Reference       string    `json:"reference"`
+	TransactionID   int64     `json:"transactionId"`
+	InitiatedBy     string    `json:"initiatedBy"`
+	Created         time.Time `json:"created"`
+	Fees            []*Fee    `json:"fees,omitempty"`
+	// sweep marks the transfer of the remaining balance of an account being closed.
+	sweep bool
+}
+
+func NewTransfer(from string, to string, amount Money, reference string, initiatedBy string) (*Transfer, error) {
+	if from == to {
+		return nil, ErrSameAccount
+	}
+	if !amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidAmount, "transfer amount must be positive")
+	}
+	return &Transfer{
+		FromAccount:     from,
+		ToAccount:       to,
+		Amount:          amount,
+		ConvertedAmount: amount,
+		Reference:       reference,
+		InitiatedBy:     initiatedBy,
+		Created:         time.Now(),
+	}, nil
+}
+
+// NewClosureSweep moves the remaining balance of an account being closed to another account. It is
+// booked like a transfer, but as part of the closure it doesn't count towards the owner's limits.
+func NewClosureSweep(from string, to string, amount Money, initiatedBy string) (*Transfer, error) {
+	t, err := NewTransfer(from, to, amount, "Account closure", initiatedBy)
+	if err != nil {
+		return nil, err
+	}
+	t.sweep = true
+	return t, nil
+}
+
+// Convert applies the exchange rate so the destination is credited in its own currency.
+func (t *Transfer) Convert(rate *ExchangeRate) error {
+	converted, err := rate.Convert(t.Amount)
+	if err != nil {
+		return err
+	}
+	if !converted.IsPositive() {
+		return errors.Wrap(ErrInvalidAmount, "converted amount rounds to zero")
+	}
+	t.ConvertedAmount = converted
+	t.ExchangeRate = rate.Rate
+	return nil
+}
+
+// Transaction builds the ledger posting for the transfer. Cross-currency transfers go through
+// the FX position accounts of both currencies so that each currency balances on its own.
+func (t *Transfer) Transaction(fxSource string, fxDestination string) *Transaction {
+	txType, description := TransferTransaction, "Transfer"
+	if t.sweep {
+		txType, description = ClosureSweepTransaction, "Account closure"
+	}
+	txn := NewTransaction(txType, t.Reference, description)
+	txn.Created = t.Created
+	txn.ExchangeRate = t.ExchangeRate
+	txn.InitiatedBy = t.InitiatedBy
+	if t.Amount.Currency == t.ConvertedAmount.Currency {
+		return txn.Debit(t.FromAccount, t.Amount).Credit(t.ToAccount, t.Amount)
+	}
+	return txn.
+		Debit(t.FromAccount, t.Amount).
+		Credit(fxSource, t.Amount).
+		Debit(fxDestination, t.ConvertedAmount).
+		Credit(t.ToAccount, t.ConvertedAmount)
+}
@@ -13,37 +13,55 @@ const (
 	UserRole  Role = "user"
 )
 
+type AccountType string
+
+const (
+	CurrentAccount  AccountType = "current"
+	SavingsAccount  AccountType = "savings"
+	InternalAccount AccountType = "internal"
+)
+
+var ErrInvalidAccountType = errors.New("invalid account type")
+
 type User struct {
-	ID                int       `json:"ID"`
+	ID                string    `json:"ID"`
 	FirstName         string    `json:"name"`
 	LastName          string    `json:"lastName"`
 	MemberSince       time.Time `json:"memberSince"`
 	EncryptedPassword string    `json:"encryptedPassword"`
 	Role              Role      `json:"role"`
+	Active            bool      `json:"active"`
 }
 
 type UserDto struct {
-	ID          int       `json:"ID"`
+	ID          string    `json:"ID"`
 	FirstName   string    `json:"name"`
 	LastName    string    `json:"lastName"`
 	MemberSince time.Time `json:"memberSince"`
 	Role        Role      `json:"role"`
+	Active      bool      `json:"active"`
 }
 
 type Account struct {
-	AccountNumber int       `json:"accountNumber"`
-	Balance       float64   `json:"balance"`
-	OwnerID       int       `json:"ownerID"`
-	Created       time.Time `json:"created"`
+	AccountNumber string `json:"accountNumber"`
+	Balance       Money  `json:"balance"`
+	Held          Money  `json:"held"`
+	Available     Money  `json:"availableBalance"`
+	// OverdraftLimit is how far below zero the balance may go; zero means no overdraft.
+	OverdraftLimit Money         `json:"overdraftLimit"`
+	Type           AccountType   `json:"type"`
+	Status         AccountStatus `json:"status"`
+	OwnerID        string        `json:"ownerID"`
+	Created        time.Time     `json:"created"`
 }
 
 type LoginRequest struct {
-	ID       int    `json:"id"`
+	ID       string `json:"id"`
 	Password string `json:"password"`
 }
 
 type LoginResponse struct {
-	ID    int    `json:"id"`
+	ID    string `json:"id"`
 	Token string `json:"token"`
 }
 
@@ -67,21 +85,70 @@ func NewUser(FirstName string, LastName string, Password string, Role string) (*
 	if err != nil {
 		return nil, err
 	}
+	id, err := NewUserID()
+	if err != nil {
+		return nil, err
+	}
 	return &User{
+		ID:                id,
 		FirstName:         FirstName,
 		LastName:          LastName,
 		EncryptedPassword: string(encpw),
 		MemberSince:       time.Now(),
 		Role:              role,
+		Active:            true,
 	}, nil
 }
 
-func NewAccount(OwnerID int) *Account {
+// ParseAccountType accepts the account types customers can open.
+func ParseAccountType(s string) (AccountType, error) {
+	switch t := AccountType(s); t {
+	case CurrentAccount, SavingsAccount:
+		return t, nil
+	}
+	return "", errors.Wrapf(ErrInvalidAccountType, "%q", s)
+}
+
+func NewAccount(OwnerID string, currency Currency) *Account {
 	return &Account{
-		Balance: 0,
-		OwnerID: OwnerID,
-		Created: time.Now(),
+		Balance:        Zero(currency),
+		Held:           Zero(currency),
+		Available:      Zero(currency),
+		OverdraftLimit: Zero(currency),
+		Type:           CurrentAccount,
+		Status:         ActiveAccount,
+		OwnerID:        OwnerID,
+		Created:        time.Now(),
+	}
+}
+
+// AvailableBalance is the part of the balance not reserved by authorization holds.
+func (a *Account) AvailableBalance() (Money, error) {
+	held := a.Held
+	held.Currency = a.Balance.Currency
+	return a.Balance.Sub(held)
+}
+
+// Payable reports whether customers may pay into and out of the account directly. Internal accounts
+// belong to the bank, loan accounts only move money through their loan and term deposit accounts
+// through their deposit.
+func (a *Account) Payable() bool {
+	return a.Type == CurrentAccount || a.Type == SavingsAccount
+}
+
+// CheckBalance reports whether the account may be left with the given available balance after a debit.
+// Internal bank accounts are allowed to go negative, customer accounts only down to their overdraft limit.
+func (a *Account) CheckBalance(balance Money) error {
+	if a.Type == InternalAccount {
+		return nil
+	}
+	if balance.Amount < -a.OverdraftLimit.Amount {
+		if a.OverdraftLimit.IsPositive() {
+			return errors.Wrapf(ErrInsufficientFunds, "account %s would exceed its overdraft limit of %s", a.AccountNumber, a.OverdraftLimit.Format())
+		}
+		return errors.Wrapf(ErrInsufficientFunds, "account %s", a.AccountNumber)
 	}
+	return nil
 }
 
 func StringToRole(s string) (Role, error) {